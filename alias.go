@@ -0,0 +1,87 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ErrAliasConflict is returned when a variable and its alias are set to different values.
+var ErrAliasConflict = errors.New("conflicting values")
+
+var (
+	aliasesMu sync.RWMutex
+	aliases   = make(map[string][]string)
+	warned    sync.Map // deprecated aliases already reported
+)
+
+// Alias registers deprecated names of the variable, e.g. after renaming REDIS_HOST to CACHE_HOST:
+//
+//	env.Alias("CACHE_HOST", "REDIS_HOST")
+//	host := env.GetString("CACHE_HOST", "localhost") // falls back to REDIS_HOST
+//
+// Every Get*, Must*, Lookup* function and Bind reading the variable tries the aliases
+// in order if the variable isn't set. Struct fields may declare aliases with a tag:
+//
+//	Host string `env:"CACHE_HOST" aliases:"REDIS_HOST"`
+//
+// A warning is logged once per alias when a deprecated alias is set, see SetLogger.
+// If the variable and an alias are set to different values, reading fails with ErrAliasConflict.
+// Registering an alias again has no effect.
+func Alias(key string, deprecated ...string) {
+	aliasesMu.Lock()
+	defer aliasesMu.Unlock()
+	for _, alias := range deprecated {
+		if !slices.Contains(aliases[key], alias) {
+			aliases[key] = append(aliases[key], alias)
+		}
+	}
+}
+
+// aliasesOf returns a copy of the aliases registered for the key.
+func aliasesOf(key string) []string {
+	aliasesMu.RLock()
+	defer aliasesMu.RUnlock()
+	return append([]string(nil), aliases[key]...)
+}
+
+// lookupAliased looks the key up, then its aliases in order.
+// It returns the value and the key it was found by, or an empty key if none is set.
+// Empty values are treated as not set: an empty variable is only returned as is
+// if none of its aliases has a value.
+func lookupAliased(src Source, key string, aliases []string) (string, string, error) {
+	value, ok, err := src.Lookup(key)
+	if err != nil {
+		return "", "", err
+	}
+	used, empty := "", ok && value == ""
+	if ok && !empty {
+		used = key
+	}
+
+	for _, alias := range aliases {
+		v, ok, err := src.Lookup(alias)
+		if err != nil {
+			return "", "", err
+		}
+		if !ok || v == "" {
+			continue
+		}
+
+		if _, reported := warned.LoadOrStore(alias, true); !reported {
+			logger().Warn("env: deprecated variable is set", "alias", alias, "key", key)
+		}
+
+		if used == "" {
+			value, used = v, alias
+		} else if v != value {
+			return "", "", fmt.Errorf("ENV %q and %q are set to %w", used, alias, ErrAliasConflict)
+		}
+	}
+
+	if used == "" && empty {
+		return "", key, nil
+	}
+	return value, used, nil
+}
@@ -0,0 +1,85 @@
+package env_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	env.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	env.ResetAliasWarnings()
+	t.Cleanup(func() { env.SetLogger(nil) })
+	return &buf
+}
+
+func TestAlias(t *testing.T) {
+	logs := captureLogs(t)
+	env.Alias("TEST_ALIAS_CACHE_HOST", "TEST_ALIAS_REDIS_HOST", "TEST_ALIAS_LEGACY_HOST")
+
+	t.Setenv("TEST_ALIAS_LEGACY_HOST", "legacy")
+	assert.Equal(t, "legacy", env.GetString("TEST_ALIAS_CACHE_HOST", "localhost"))
+	assert.Contains(t, logs.String(), "alias=TEST_ALIAS_LEGACY_HOST key=TEST_ALIAS_CACHE_HOST")
+
+	// aliases are resolved in order
+	t.Setenv("TEST_ALIAS_REDIS_HOST", "redis")
+	_, err := env.LookupSecret[string]("TEST_ALIAS_CACHE_HOST")
+	assert.True(t, errors.Is(err, env.ErrAliasConflict))
+	os.Unsetenv("TEST_ALIAS_LEGACY_HOST")
+	assert.Equal(t, "redis", env.MustString("TEST_ALIAS_CACHE_HOST"))
+
+	// the new name wins, conflicting values are refused
+	t.Setenv("TEST_ALIAS_CACHE_HOST", "redis")
+	assert.Equal(t, "redis", env.MustString("TEST_ALIAS_CACHE_HOST"))
+	t.Setenv("TEST_ALIAS_CACHE_HOST", "cache")
+	assert.PanicsWithError(t, `failed to read required ENV "TEST_ALIAS_CACHE_HOST": ENV "TEST_ALIAS_CACHE_HOST" and "TEST_ALIAS_REDIS_HOST" are set to conflicting values`, func() {
+		env.MustString("TEST_ALIAS_CACHE_HOST")
+	})
+
+	assert.Contains(t, env.Registered(), "TEST_ALIAS_REDIS_HOST")
+}
+
+func TestBindAliases(t *testing.T) {
+	logs := captureLogs(t)
+	t.Setenv("TEST_ALIAS_OLD_PORT", "6379")
+
+	type config struct {
+		Port int `env:"TEST_ALIAS_PORT" aliases:"TEST_ALIAS_OLDER_PORT, TEST_ALIAS_OLD_PORT" default:"1"`
+	}
+	var cfg config
+	require.NoError(t, env.Bind(&cfg))
+	assert.Equal(t, 6379, cfg.Port)
+	assert.Contains(t, logs.String(), "alias=TEST_ALIAS_OLD_PORT key=TEST_ALIAS_PORT")
+
+	t.Setenv("TEST_ALIAS_PORT", "6380")
+	assert.True(t, errors.Is(env.Bind(&cfg), env.ErrAliasConflict))
+}
+
+func TestAliasEmpty(t *testing.T) {
+	env.Alias("TEST_ALIAS_EMPTY_HOST", "TEST_ALIAS_EMPTY_OLD_HOST")
+
+	// empty values are not set
+	t.Setenv("TEST_ALIAS_EMPTY_HOST", "")
+	assert.Equal(t, "", env.GetString("TEST_ALIAS_EMPTY_HOST", ""))
+	t.Setenv("TEST_ALIAS_EMPTY_OLD_HOST", "redis")
+	assert.Equal(t, "redis", env.GetString("TEST_ALIAS_EMPTY_HOST", "localhost"))
+	assert.Equal(t, "redis", env.MustString("TEST_ALIAS_EMPTY_HOST"))
+
+	type config struct {
+		Host string `env:"TEST_ALIAS_EMPTY_HOST"`
+	}
+	var cfg config
+	require.NoError(t, env.Bind(&cfg))
+	assert.Equal(t, "redis", cfg.Host)
+
+	t.Setenv("TEST_ALIAS_EMPTY_HOST", "cache")
+	t.Setenv("TEST_ALIAS_EMPTY_OLD_HOST", "")
+	assert.Equal(t, "cache", env.MustString("TEST_ALIAS_EMPTY_HOST"))
+}
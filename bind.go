@@ -0,0 +1,344 @@
+package env
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validator is implemented by config structs which check their values after binding.
+type Validator interface {
+	Validate() error
+}
+
+// Bind fills the fields of the struct pointed to by v from the current source.
+// Supported field tags:
+//   - env:"KEY" - the variable name, fields without it are skipped, nested structs are bound recursively;
+//   - aliases:"OLD_KEY,OLDER_KEY" - deprecated names tried in order if the variable isn't set, see Alias;
+//   - default:"value" - the value used when the variable is not set or empty, it may reference other
+//     variables, e.g. default:"http://${HOST}:${PORT}"; fields bound to the referenced keys
+//     are resolved first, reference cycles fail with ErrCycle;
+//   - required:"true" - the variable must be set and not empty, unless there is a default;
+//   - sep:";" - the separator of slice values, default is ",";
+//   - secret:"true" - the value is never printed in errors and diffs, as for Secret fields
+//     and keys matching DefaultSecretKeyPatterns;
+//   - required_if:"KEY=value,KEY2=value2" - the variable is required when any condition holds, see RequiredIf;
+//   - required_with:"KEY,KEY2" - the variable is required when any of the keys is set, see RequiredWith;
+//   - required_without:"KEY,KEY2" - the variable is required when any of the keys is not set, see RequiredWithout;
+//   - excluded_with:"KEY,KEY2" - the variable must not be set when any of the keys is set, see ExcludedWith;
+//   - exclusive:"group" - at most one variable of the group may be set, see MutuallyExclusive.
+//
+// Conditional rules are checked after binding, keys of the struct fields are checked
+// with the resolved field values, including defaults, other keys with the current source.
+// Field types may be strings, booleans, numbers, time.Duration, slices and pointers of them,
+// and any type implementing encoding.TextUnmarshaler, e.g. Secret, Version or Rate.
+// All errors are collected and returned joined. If the struct implements Validator,
+// Validate is called after successful binding.
+//
+//	type Config struct {
+//		Port     int                `env:"HTTP_PORT" default:"8080"`
+//		Password env.Secret[string] `env:"DB_PASSWORD" required:"true"`
+//	}
+//	var cfg Config
+//	if err := env.Bind(&cfg); err != nil {
+//		...
+//	}
+func Bind(v any) error {
+	return bindStruct(v, nil)
+}
+
+// bindStruct binds v, fields whose variables were scrubbed keep their values from prev, if it's not nil.
+func bindStruct(v, prev any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind target must be a non-nil pointer to a struct, got %T", v)
+	}
+
+	fields := structFields(rv.Elem(), "")
+	rules, err := tagRules(fields)
+	if err != nil {
+		return err
+	}
+
+	b := newBinding(fields)
+	if prev != nil {
+		b.prev = structFields(reflect.ValueOf(prev).Elem(), "")
+	}
+	if err := errors.Join(b.bind(), checkRules(rules, b.ruleLookup)); err != nil {
+		return err
+	}
+
+	if validator, ok := v.(Validator); ok {
+		return validator.Validate()
+	}
+	return nil
+}
+
+// boundField is a struct field with the env tag.
+type boundField struct {
+	path  string // e.g. "DB.Host"
+	key   string
+	tag   reflect.StructTag
+	value reflect.Value
+}
+
+// aliases returns the deprecated names of the variable from the aliases tag, see Alias.
+func (f boundField) aliases() []string {
+	return splitTag(f.tag.Get("aliases"))
+}
+
+// secret reports whether the field value must never be printed.
+func (f boundField) secret() bool {
+	if _, ok := f.value.Interface().(secretValue); ok {
+		return true
+	}
+	return f.tag.Get("secret") == "true" || matchKey(DefaultSecretKeyPatterns, f.key)
+}
+
+// String formats the field value, secret values are masked.
+func (f boundField) String() string {
+	if f.secret() {
+		return Redacted
+	}
+	v := f.value
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "<nil>"
+		}
+		v = v.Elem()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// structFields lists the exported fields with the env tag, nested structs are walked recursively.
+func structFields(rv reflect.Value, prefix string) []boundField {
+	var fields []boundField
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		path := prefix + sf.Name
+		key := sf.Tag.Get("env")
+		if key == "" || key == "-" {
+			if key == "" && sf.Type.Kind() == reflect.Struct && !reflect.PointerTo(sf.Type).Implements(textUnmarshalerType) {
+				fields = append(fields, structFields(rv.Field(i), path+".")...)
+			}
+			continue
+		}
+
+		fields = append(fields, boundField{path: path, key: key, tag: sf.Tag, value: rv.Field(i)})
+	}
+	return fields
+}
+
+// binding resolves the values of the fields in dependency order,
+// as defaults may reference other fields, e.g. default:"${WRITE_DSN}".
+type binding struct {
+	fields []boundField
+	byKey  map[string]int
+	state  []int // 0 - unresolved, 1 - resolving, 2 - resolved
+	values []string
+	found  []bool
+	errs   []error // resolution error per field
+	prev   []boundField
+}
+
+func newBinding(fields []boundField) *binding {
+	b := &binding{
+		fields: fields,
+		byKey:  make(map[string]int, len(fields)),
+		state:  make([]int, len(fields)),
+		values: make([]string, len(fields)),
+		found:  make([]bool, len(fields)),
+		errs:   make([]error, len(fields)),
+	}
+	for i, f := range fields {
+		if _, ok := b.byKey[f.key]; !ok {
+			b.byKey[f.key] = i
+		}
+	}
+	return b
+}
+
+// bind resolves and sets every field, all errors are returned joined.
+func (b *binding) bind() error {
+	var errs []error
+	for i, f := range b.fields {
+		if err := b.bindField(i); err != nil {
+			errs = append(errs, fmt.Errorf("field %s: %w", f.path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *binding) bindField(i int) error {
+	f := b.fields[i]
+	value, ok, err := b.resolve(i, nil)
+	if errors.Is(err, ErrConsumed) && b.prev != nil {
+		f.value.Set(b.prev[i].value)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if f.tag.Get("required") == "true" {
+			return fmt.Errorf("ENV %q: %w", f.key, ErrNotSet)
+		}
+		return nil
+	}
+
+	sep := f.tag.Get("sep")
+	if sep == "" {
+		sep = ","
+	}
+	if err := setValue(f.value, value, sep); err != nil {
+		if f.secret() {
+			err = secretError(err)
+		}
+		return fmt.Errorf("ENV %q: %w", f.key, err)
+	}
+	return nil
+}
+
+// resolve returns the raw value of the field: the variable, its aliases or the expanded default.
+// Empty variables are treated as not set, as by Get* and Must* functions.
+// ${KEY} references in defaults are resolved with the fields bound to KEY first,
+// then with the variables. path is the chain of keys being resolved, for cycle detection.
+func (b *binding) resolve(i int, path []string) (string, bool, error) {
+	f := b.fields[i]
+	switch b.state[i] {
+	case 1:
+		return "", false, fmt.Errorf("default of ENV %q: %w: %s", f.key, ErrCycle, strings.Join(append(path, f.key), " -> "))
+	case 2:
+		return b.values[i], b.found[i], b.errs[i]
+	}
+
+	b.state[i] = 1
+	value, ok, err := b.lookup(i, append(path, f.key))
+	b.state[i] = 2
+	b.values[i], b.found[i], b.errs[i] = value, ok, err
+	return value, ok, err
+}
+
+func (b *binding) lookup(i int, path []string) (string, bool, error) {
+	f := b.fields[i]
+	value, ok, err := lookupEnv(f.key, f.aliases()...)
+	if err != nil || ok && value != "" {
+		return value, ok, err
+	}
+
+	def, ok := f.tag.Lookup("default")
+	if !ok {
+		return "", false, nil
+	}
+
+	value, err = expandRefs(def, func(key string) (string, error) {
+		if j, ok := b.byKey[key]; ok {
+			value, _, err := b.resolve(j, path)
+			return value, err
+		}
+		value, _, err := lookupEnv(key)
+		return value, err
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// ruleLookup returns the resolved value of the field bound to the key,
+// or the value of the variable if there is no such field.
+func (b *binding) ruleLookup(key string) (string, bool) {
+	if i, ok := b.byKey[key]; ok {
+		value, ok, err := b.resolve(i, nil)
+		if errors.Is(err, ErrConsumed) {
+			return "", true
+		}
+		return value, err == nil && ok && value != ""
+	}
+	return peekEnv(key)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setValue parses the string into the value.
+func setValue(rv reflect.Value, value, sep string) error {
+	if rv.CanAddr() {
+		if u, ok := rv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(value))
+		}
+	}
+	if rv.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		rv.SetInt(int64(d))
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(value)
+	case reflect.Bool:
+		b, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(n)
+	case reflect.Pointer:
+		p := reflect.New(rv.Type().Elem())
+		if err := setValue(p.Elem(), value, sep); err != nil {
+			return err
+		}
+		rv.Set(p)
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			rv.SetBytes([]byte(value))
+			return nil
+		}
+		var parts []string
+		if value != "" {
+			parts = strings.Split(value, sep)
+		}
+		s := reflect.MakeSlice(rv.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setValue(s.Index(i), strings.TrimSpace(part), sep); err != nil {
+				return err
+			}
+		}
+		rv.Set(s)
+	default:
+		return fmt.Errorf("unsupported type %s", rv.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package env_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bindDBConfig struct {
+	Host     string             `env:"TEST_BIND_DB_HOST" default:"localhost"`
+	Password env.Secret[string] `env:"TEST_BIND_DB_PASSWORD" required:"true"`
+}
+
+type bindConfig struct {
+	Port     int           `env:"TEST_BIND_PORT" default:"8080"`
+	Debug    bool          `env:"TEST_BIND_DEBUG"`
+	Timeout  time.Duration `env:"TEST_BIND_TIMEOUT" default:"5s"`
+	Ratio    *float64      `env:"TEST_BIND_RATIO"`
+	Hosts    []string      `env:"TEST_BIND_HOSTS"`
+	Ports    []uint16      `env:"TEST_BIND_PORTS" sep:";"`
+	Version  env.Version   `env:"TEST_BIND_VERSION" default:"1.0.0"`
+	DB       bindDBConfig
+	Ignored  string `env:"-"`
+	internal string
+}
+
+func TestBind(t *testing.T) {
+	t.Setenv("TEST_BIND_DEBUG", "true")
+	t.Setenv("TEST_BIND_RATIO", "0.5")
+	t.Setenv("TEST_BIND_HOSTS", "a, b")
+	t.Setenv("TEST_BIND_PORTS", "80;443")
+	t.Setenv("TEST_BIND_DB_PASSWORD", "bind-s3cr3t")
+
+	var cfg bindConfig
+	require.NoError(t, env.Bind(&cfg))
+	assert.Equal(t, 8080, cfg.Port)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+	assert.Equal(t, 0.5, *cfg.Ratio)
+	assert.Equal(t, []string{"a", "b"}, cfg.Hosts)
+	assert.Equal(t, []uint16{80, 443}, cfg.Ports)
+	assert.Equal(t, "1.0.0", cfg.Version.String())
+	assert.Equal(t, "localhost", cfg.DB.Host)
+	assert.Equal(t, "bind-s3cr3t", cfg.DB.Password.Reveal())
+
+	assert.Error(t, env.Bind(cfg))
+	assert.Error(t, env.Bind((*bindConfig)(nil)))
+}
+
+func TestBindErrors(t *testing.T) {
+	t.Setenv("TEST_BIND_PORT", "http")
+	t.Setenv("TEST_BIND_TIMEOUT", "soon")
+
+	var cfg bindConfig
+	err := env.Bind(&cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "field Port")
+	assert.Contains(t, err.Error(), "field Timeout")
+	assert.Contains(t, err.Error(), "field DB.Password")
+	assert.True(t, errors.Is(err, env.ErrNotSet))
+
+	type secretConfig struct {
+		Token int `env:"TEST_BIND_API_TOKEN"`
+	}
+	t.Setenv("TEST_BIND_API_TOKEN", "tok-3n-value")
+	err = env.Bind(&secretConfig{})
+	require.Error(t, err)
+	assert.False(t, strings.Contains(err.Error(), "tok-3n-value"))
+}
+
+func TestBindEmpty(t *testing.T) {
+	t.Setenv("TEST_BIND_PORT", "")
+	t.Setenv("TEST_BIND_DB_PASSWORD", "")
+
+	var cfg bindConfig
+	err := env.Bind(&cfg)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "field Port")
+	assert.Contains(t, err.Error(), `field DB.Password: ENV "TEST_BIND_DB_PASSWORD": not set`)
+
+	t.Setenv("TEST_BIND_DB_PASSWORD", "bind-s3cr3t")
+	require.NoError(t, env.Bind(&cfg))
+	assert.Equal(t, 8080, cfg.Port)
+}
+
+type validatedConfig struct {
+	Min int `env:"TEST_BIND_MIN" default:"1"`
+	Max int `env:"TEST_BIND_MAX" default:"10"`
+}
+
+func (c *validatedConfig) Validate() error {
+	if c.Min > c.Max {
+		return errors.New("min must not exceed max")
+	}
+	return nil
+}
+
+func TestBindValidate(t *testing.T) {
+	assert.NoError(t, env.Bind(&validatedConfig{}))
+
+	t.Setenv("TEST_BIND_MIN", "20")
+	assert.EqualError(t, env.Bind(&validatedConfig{}), "min must not exceed max")
+}
@@ -0,0 +1,52 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// CredentialsDirectoryEnv is the variable systemd sets to the directory with the service credentials.
+const CredentialsDirectoryEnv = "CREDENTIALS_DIRECTORY"
+
+// DockerSecretsDir is the directory Docker mounts secrets into.
+var DockerSecretsDir = "/run/secrets"
+
+// CredentialDirs returns the existing credential directories in the lookup order:
+// systemd $CREDENTIALS_DIRECTORY first, then DockerSecretsDir.
+func CredentialDirs() []string {
+	var dirs []string
+	if dir := os.Getenv(CredentialsDirectoryEnv); dir != "" {
+		dirs = append(dirs, filepath.Clean(dir))
+	}
+	if DockerSecretsDir != "" {
+		dirs = append(dirs, filepath.Clean(DockerSecretsDir))
+	}
+
+	existing := dirs[:0]
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			existing = append(existing, dir)
+		}
+	}
+	return existing
+}
+
+// CredentialsSource creates a Source exposing systemd credentials and Docker secrets
+// found by CredentialDirs as variables. File names are mapped with EnvKeyName by default,
+// so "db-password" or "db_password" is available as "DB_PASSWORD"; use WithKeyMapper to change it.
+// All values are registered as secrets, so every Redactor masks them.
+// If no directory is found, the source exposes no variables.
+//
+//	env.SetSource(env.Chain(env.OS, env.CredentialsSource()))
+//	password := env.MustString("DB_PASSWORD")
+func CredentialsSource(opts ...DirOption) Source {
+	opts = append([]DirOption{WithKeyMapper(EnvKeyName)}, opts...)
+	opts = append(opts, AsSecrets())
+
+	dirs := CredentialDirs()
+	sources := make([]Source, 0, len(dirs))
+	for _, dir := range dirs {
+		sources = append(sources, DirSource(dir, opts...))
+	}
+	return Chain(sources...)
+}
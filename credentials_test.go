@@ -0,0 +1,43 @@
+package env_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialsSource(t *testing.T) {
+	systemd := t.TempDir()
+	docker := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(systemd, "db-password"), []byte("systemd-cred-value\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(docker, "db_password"), []byte("docker-secret-value"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(docker, "api_token"), []byte("docker-token-value"), 0o600))
+
+	t.Setenv(env.CredentialsDirectoryEnv, systemd)
+	defer func(dir string) { env.DockerSecretsDir = dir }(env.DockerSecretsDir)
+	env.DockerSecretsDir = docker
+
+	assert.Equal(t, []string{systemd, docker}, env.CredentialDirs())
+
+	env.SetSource(env.Chain(env.OS, env.CredentialsSource()))
+	defer env.SetSource(nil)
+
+	// systemd credentials take precedence over Docker secrets
+	assert.Equal(t, "systemd-cred-value", env.MustString("DB_PASSWORD"))
+	assert.Equal(t, "docker-token-value", env.MustString("API_TOKEN"))
+
+	// process environment takes precedence over credentials
+	t.Setenv("API_TOKEN", "from-env")
+	assert.Equal(t, "from-env", env.MustString("API_TOKEN"))
+
+	// values are registered as secrets
+	assert.Equal(t, "password="+env.Redacted, env.NewRedactor().Redact("password=systemd-cred-value"))
+
+	// missing directories are skipped
+	env.DockerSecretsDir = filepath.Join(docker, "missing")
+	assert.Equal(t, []string{systemd}, env.CredentialDirs())
+}
@@ -0,0 +1,252 @@
+package env
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// EncryptedPrefix is the prefix of encrypted values.
+// Encrypted value format: enc:v1:<cipher>:<key id>:<base64url nonce and ciphertext>
+const EncryptedPrefix = "enc:"
+
+// Supported ciphers of encrypted values.
+const (
+	AES256GCM         = "aes256gcm"
+	XChaCha20Poly1305 = "xchacha20poly1305"
+)
+
+// KeySize is the size of encryption keys in bytes.
+const KeySize = 32
+
+// defaultKeyID is used for keys specified without an id.
+const defaultKeyID = "default"
+
+// Keyring holds encryption keys by id.
+// The first added key is the primary one and is used for encryption,
+// all keys are used for decryption, so keys can be rotated by adding
+// a new primary key and keeping the old ones until values are re-encrypted.
+type Keyring struct {
+	// Cipher used by Encrypt, default is AES256GCM.
+	Cipher string
+
+	ids  []string
+	keys map[string][]byte
+}
+
+// NewKeyring creates an empty keyring.
+func NewKeyring() *Keyring {
+	return &Keyring{keys: make(map[string][]byte)}
+}
+
+// Add adds the key with the given id to the keyring.
+func (k *Keyring) Add(id string, key []byte) error {
+	if id == "" || strings.ContainsAny(id, ":,\n") {
+		return fmt.Errorf("invalid key id %q", id)
+	}
+	if len(key) != KeySize {
+		return fmt.Errorf("key %q must be %d bytes long", id, KeySize)
+	}
+	if _, ok := k.keys[id]; ok {
+		return fmt.Errorf("duplicate key id %q", id)
+	}
+
+	k.ids = append(k.ids, id)
+	k.keys[id] = append([]byte(nil), key...)
+
+	return nil
+}
+
+// ParseKeyring parses a list of keys separated by commas or new lines.
+// Each key is "<id>:<base64 key>" or just "<base64 key>" for a single key without id.
+// The first key is the primary one.
+func ParseKeyring(spec string) (*Keyring, error) {
+	k := NewKeyring()
+	for _, line := range strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == '\n' || r == '\r' }) {
+		line = strings.TrimSpace(line)
+		// skip empty lines and comments
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(line, ":")
+		if !ok {
+			id, encoded = defaultKeyID, line
+		}
+
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("key %q must be base64 encoded", id)
+		}
+		if err := k.Add(strings.TrimSpace(id), key); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(k.ids) == 0 {
+		return nil, errors.New("keyring must contain at least one key")
+	}
+
+	return k, nil
+}
+
+// LoadKeyring reads keys from the key file, see ParseKeyring for the format.
+func LoadKeyring(path string) (*Keyring, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	return ParseKeyring(string(data))
+}
+
+// KeyringFromEnv reads keys from the master environment variable, see ParseKeyring for the format.
+// The variable is read from the process environment, not from the current Source.
+func KeyringFromEnv(key string) (*Keyring, error) {
+	value, exists := os.LookupEnv(key)
+	if !exists || value == "" {
+		return nil, fmt.Errorf("ENV %q is %w", key, ErrNotSet)
+	}
+	return ParseKeyring(value)
+}
+
+// GenerateKey returns a new random key encoded the way ParseKeyring expects it.
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// newAEAD creates an AEAD cipher by name.
+func newAEAD(name string, key []byte) (cipher.AEAD, error) {
+	switch name {
+	case AES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case XChaCha20Poly1305:
+		return chacha20poly1305.NewX(key)
+	}
+	return nil, fmt.Errorf("unsupported cipher %q", name)
+}
+
+// IsEncrypted reports whether the value has the encrypted value prefix.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, EncryptedPrefix)
+}
+
+// Encrypt encrypts plaintext with the primary key of the keyring.
+func Encrypt(kr *Keyring, plaintext string) (string, error) {
+	if kr == nil || len(kr.ids) == 0 {
+		return "", errors.New("keyring is empty")
+	}
+
+	name := kr.Cipher
+	if name == "" {
+		name = AES256GCM
+	}
+	id := kr.ids[0]
+
+	aead, err := newAEAD(name, kr.keys[id])
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	// the header is authenticated, so cipher and key id can't be swapped
+	header := EncryptedPrefix + "v1:" + name + ":" + id + ":"
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(header))
+
+	return header + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value produced by Encrypt with the matching key of the keyring.
+// The returned error never contains the value.
+func Decrypt(kr *Keyring, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", errors.New("value is not encrypted")
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(value, EncryptedPrefix), ":", 4)
+	if len(parts) != 4 {
+		return "", errors.New("malformed encrypted value")
+	}
+	version, name, id, payload := parts[0], parts[1], parts[2], parts[3]
+	if version != "v1" {
+		return "", fmt.Errorf("unsupported encrypted value version %q", version)
+	}
+
+	if kr == nil {
+		return "", errors.New("keyring is empty")
+	}
+	key, ok := kr.keys[id]
+	if !ok {
+		return "", fmt.Errorf("unknown key id %q", id)
+	}
+
+	aead, err := newAEAD(name, key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	header := EncryptedPrefix + version + ":" + name + ":" + id + ":"
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(header))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value with key %q", id)
+	}
+
+	return string(plaintext), nil
+}
+
+// Reencrypt decrypts the value with any key of the keyring and encrypts it
+// with the primary key. It's used to rotate keys.
+func Reencrypt(kr *Keyring, value string) (string, error) {
+	plaintext, err := Decrypt(kr, value)
+	if err != nil {
+		return "", err
+	}
+	return Encrypt(kr, plaintext)
+}
+
+// DecryptingSource wraps the source, so encrypted values ("enc:" prefixed)
+// are transparently decrypted with the keyring. Plain values are returned as is.
+// Decrypted values are registered as secrets, so every Redactor masks them.
+//
+//	kr, err := env.KeyringFromEnv("ENV_MASTER_KEY")
+//	...
+//	env.SetSource(env.DecryptingSource(env.OS, kr))
+func DecryptingSource(src Source, kr *Keyring) Source {
+	return wrap(src, func(key string) (string, bool, error) {
+		value, ok, err := src.Lookup(key)
+		if err != nil || !ok || !IsEncrypted(value) {
+			return value, ok, err
+		}
+
+		plaintext, err := Decrypt(kr, value)
+		if err != nil {
+			return "", true, err
+		}
+		secrets.add(plaintext)
+
+		return plaintext, true, nil
+	})
+}
@@ -0,0 +1,171 @@
+package env_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestKeyring(t *testing.T, ids ...string) *env.Keyring {
+	t.Helper()
+	var spec []string
+	for _, id := range ids {
+		key, err := env.GenerateKey()
+		require.NoError(t, err)
+		spec = append(spec, id+":"+key)
+	}
+	kr, err := env.ParseKeyring(strings.Join(spec, ","))
+	require.NoError(t, err)
+	return kr
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	for _, cipher := range []string{env.AES256GCM, env.XChaCha20Poly1305} {
+		kr := newTestKeyring(t, "k1")
+		kr.Cipher = cipher
+
+		enc, err := env.Encrypt(kr, "pa55w0rd")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(enc, "enc:v1:"+cipher+":k1:"), enc)
+		assert.NotContains(t, enc, "pa55w0rd")
+
+		dec, err := env.Decrypt(kr, enc)
+		assert.NoError(t, err)
+		assert.Equal(t, "pa55w0rd", dec)
+
+		// tampered header
+		other := "aes256gcm"
+		if cipher == other {
+			other = "xchacha20poly1305"
+		}
+		_, err = env.Decrypt(kr, strings.Replace(enc, cipher, other, 1))
+		assert.Error(t, err)
+
+		// wrong key
+		_, err = env.Decrypt(newTestKeyring(t, "k1"), enc)
+		assert.EqualError(t, err, `failed to decrypt value with key "k1"`)
+	}
+
+	_, err := env.Decrypt(newTestKeyring(t, "k1"), "enc:v1:aes256gcm:k2:AAAA")
+	assert.EqualError(t, err, `unknown key id "k2"`)
+	_, err = env.Decrypt(newTestKeyring(t, "k1"), "enc:v2:aes256gcm:k1:AAAA")
+	assert.Error(t, err)
+	_, err = env.Decrypt(newTestKeyring(t, "k1"), "plain")
+	assert.Error(t, err)
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey, err := env.GenerateKey()
+	require.NoError(t, err)
+	newKey, err := env.GenerateKey()
+	require.NoError(t, err)
+
+	old, err := env.ParseKeyring("2023:" + oldKey)
+	require.NoError(t, err)
+	enc, err := env.Encrypt(old, "rotated-secret")
+	require.NoError(t, err)
+
+	kr, err := env.ParseKeyring("2024:" + newKey + ",2023:" + oldKey)
+	require.NoError(t, err)
+
+	// old values are still readable
+	dec, err := env.Decrypt(kr, enc)
+	assert.NoError(t, err)
+	assert.Equal(t, "rotated-secret", dec)
+
+	// re-encrypted values use the new primary key
+	reenc, err := env.Reencrypt(kr, enc)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(reenc, "enc:v1:aes256gcm:2024:"))
+	_, err = env.Decrypt(old, reenc)
+	assert.Error(t, err)
+}
+
+func TestParseKeyring(t *testing.T) {
+	key, err := env.GenerateKey()
+	require.NoError(t, err)
+
+	kr, err := env.ParseKeyring(key)
+	assert.NoError(t, err)
+	enc, err := env.Encrypt(kr, "value")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(enc, "enc:v1:aes256gcm:default:"))
+
+	_, err = env.ParseKeyring("")
+	assert.Error(t, err)
+	_, err = env.ParseKeyring("k1:not-base64!")
+	assert.Error(t, err)
+	_, err = env.ParseKeyring("k1:c2hvcnQ=")
+	assert.Error(t, err)
+	_, err = env.ParseKeyring("k1:" + key + ",k1:" + key)
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "keys")
+	require.NoError(t, os.WriteFile(path, []byte("# newest first\nk2:"+key+"\n\nk1:"+key+"\n"), 0o600))
+	kr, err = env.LoadKeyring(path)
+	assert.NoError(t, err)
+	enc, err = env.Encrypt(kr, "value")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(enc, "enc:v1:aes256gcm:k2:"))
+
+	_, err = env.KeyringFromEnv("TEST_ENV_2")
+	assert.Error(t, err)
+	t.Setenv("TEST_MASTER_KEY", "k3:"+key)
+	kr, err = env.KeyringFromEnv("TEST_MASTER_KEY")
+	assert.NoError(t, err)
+	enc, err = env.Encrypt(kr, "value")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(enc, "enc:v1:aes256gcm:k3:"))
+}
+
+func TestDecryptingSource(t *testing.T) {
+	kr := newTestKeyring(t, "k1")
+	encPass, err := env.Encrypt(kr, "db-pa55")
+	require.NoError(t, err)
+	encTimeout, err := env.Encrypt(kr, "30s")
+	require.NoError(t, err)
+
+	t.Setenv("TEST_DB_PASS", encPass)
+	t.Setenv("TEST_TIMEOUT", encTimeout)
+	t.Setenv("TEST_PLAIN", "plain")
+	t.Setenv("TEST_BROKEN", "enc:v1:aes256gcm:k1:AAAA")
+
+	env.SetSource(env.DecryptingSource(env.OS, kr))
+	defer env.SetSource(nil)
+
+	assert.Equal(t, "db-pa55", env.MustString("TEST_DB_PASS"))
+	assert.Equal(t, "db-pa55", env.GetString("TEST_DB_PASS", ""))
+	assert.Equal(t, "db-pa55", env.MustSecret[string]("TEST_DB_PASS").Reveal())
+	assert.Equal(t, 30*time.Second, env.MustDuration("TEST_TIMEOUT"))
+	assert.Equal(t, "plain", env.MustString("TEST_PLAIN"))
+
+	assert.Equal(t, "fallback", env.GetString("TEST_BROKEN", "fallback"))
+	assert.PanicsWithError(t, `failed to read required ENV "TEST_BROKEN": malformed encrypted value`, func() { env.MustString("TEST_BROKEN") })
+	_, err = env.LookupSecret[string]("TEST_BROKEN")
+	assert.Error(t, err)
+
+	// decrypted values are masked by redactors
+	assert.Equal(t, "dsn=[REDACTED]", env.NewRedactor().Redact("dsn=db-pa55"))
+}
+
+func TestDecryptingSourceKeys(t *testing.T) {
+	kr := newTestKeyring(t, "k1")
+	encToken, err := env.Encrypt(kr, "tok-prb-xyz")
+	require.NoError(t, err)
+
+	layer := env.MapSource{"TEST_PRB_API_TOKEN": encToken, "TEST_PRB_TIMOUT": "5s"}
+	env.SetSource(env.DecryptingSource(env.NewEnv(env.NewLayer("config", layer)), kr))
+	defer env.SetSource(nil)
+
+	// values are masked without being read through secret getters
+	assert.Equal(t, "token=[REDACTED]", env.NewRedactor().Redact("token=tok-prb-xyz"))
+
+	env.Register("TEST_PRB_TIMEOUT", "TEST_PRB_API_TOKEN")
+	assert.Equal(t, []env.UnknownVar{{Key: "TEST_PRB_TIMOUT", Suggestion: "TEST_PRB_TIMEOUT"}}, env.FindUnknown("TEST_PRB_"))
+}
@@ -0,0 +1,208 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// DefaultDirMaxSize is the default limit of a file size read by DirSource.
+const DefaultDirMaxSize = 1 << 20
+
+// DirectorySource is a Source exposing each file of a directory as a variable,
+// e.g. a Kubernetes ConfigMap or Secret volume. The directory is read on every lookup,
+// so updates published by Kubernetes with the atomic "..data" symlink swap are seen immediately.
+type DirectorySource struct {
+	path     string
+	mapKey   func(name string) string
+	dotfiles bool
+	maxSize  int64
+	secret   bool
+}
+
+// DirOption configures a DirectorySource.
+type DirOption func(*DirectorySource)
+
+// WithKeyMapper sets the function mapping file names to variable names, e.g. EnvKeyName.
+// By default file names are used as is.
+func WithKeyMapper(fn func(name string) string) DirOption {
+	return func(d *DirectorySource) { d.mapKey = fn }
+}
+
+// IncludeDotfiles exposes files starting with a dot, which are ignored by default.
+// Kubernetes service entries ("..data" and timestamped directories) are always ignored.
+func IncludeDotfiles() DirOption {
+	return func(d *DirectorySource) { d.dotfiles = true }
+}
+
+// WithMaxSize limits the size of a file, default is DefaultDirMaxSize.
+// Lookup of a larger file fails with an error.
+func WithMaxSize(size int64) DirOption {
+	return func(d *DirectorySource) { d.maxSize = size }
+}
+
+// AsSecrets registers every read value as a secret, so every Redactor masks it.
+func AsSecrets() DirOption {
+	return func(d *DirectorySource) { d.secret = true }
+}
+
+// DirSource creates a Source exposing each file of the directory as a variable.
+// A single trailing newline of the file content is trimmed.
+// If the directory doesn't exist, no variables are exposed.
+//
+//	env.SetSource(env.Chain(env.OS, env.DirSource("/etc/config", env.WithKeyMapper(env.EnvKeyName))))
+func DirSource(path string, opts ...DirOption) *DirectorySource {
+	d := &DirectorySource{path: path, maxSize: DefaultDirMaxSize}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Path returns the directory path.
+func (d *DirectorySource) Path() string {
+	return d.path
+}
+
+// Lookup implements Source.
+func (d *DirectorySource) Lookup(key string) (string, bool, error) {
+	names, err := d.names()
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, name := range names {
+		if d.key(name) == key {
+			value, err := d.read(name)
+			if err != nil {
+				return "", false, fmt.Errorf("failed to read ENV %q from %s: %w", key, d.path, err)
+			}
+			return value, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// Values reads all variables of the directory.
+func (d *DirectorySource) Values() (map[string]string, error) {
+	names, err := d.names()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		value, err := d.read(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filepath.Join(d.path, name), err)
+		}
+		values[d.key(name)] = value
+	}
+
+	return values, nil
+}
+
+// Keys implements KeyLister.
+func (d *DirectorySource) Keys() ([]string, error) {
+	names, err := d.names()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		keys = append(keys, d.key(name))
+	}
+	return keys, nil
+}
+
+// Location implements Locator.
+func (d *DirectorySource) Location(key string) string {
+	names, err := d.names()
+	if err != nil {
+		return ""
+	}
+	for _, name := range names {
+		if d.key(name) == key {
+			return filepath.Join(d.path, name)
+		}
+	}
+	return ""
+}
+
+// names lists the exposed file names, symlinks are followed.
+func (d *DirectorySource) names() ([]string, error) {
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, "..") || !d.dotfiles && strings.HasPrefix(name, ".") {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(d.path, name))
+		if err != nil || !info.Mode().IsRegular() {
+			// dangling symlinks appear for a moment during the "..data" swap
+			continue
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func (d *DirectorySource) key(name string) string {
+	if d.mapKey != nil {
+		return d.mapKey(name)
+	}
+	return name
+}
+
+func (d *DirectorySource) read(name string) (string, error) {
+	f, err := os.Open(filepath.Join(d.path, name))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(io.LimitReader(f, d.maxSize+1))
+	if err != nil {
+		return "", err
+	}
+	if int64(len(data)) > d.maxSize {
+		return "", fmt.Errorf("file exceeds the size limit of %d bytes", d.maxSize)
+	}
+
+	value := trimNewline(string(data))
+	if d.secret {
+		secrets.add(value)
+	}
+
+	return value, nil
+}
+
+// EnvKeyName maps a file name to a conventional variable name:
+// letters are upper-cased and any other character except digits is replaced with "_",
+// e.g. "db.password" -> "DB_PASSWORD", "api-key" -> "API_KEY".
+func EnvKeyName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			return unicode.ToUpper(r)
+		case r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
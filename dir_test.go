@@ -0,0 +1,91 @@
+package env_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigMap mimics the Kubernetes volume layout: files live in a timestamped
+// directory, "..data" points to it and each key is a symlink through "..data".
+func writeConfigMap(t *testing.T, dir, version string, files map[string]string) {
+	t.Helper()
+
+	data := filepath.Join(dir, "..ts_"+version)
+	require.NoError(t, os.Mkdir(data, 0o755))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(data, name), []byte(content), 0o644))
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); os.IsNotExist(err) {
+			require.NoError(t, os.Symlink(filepath.Join("..data", name), link))
+		}
+	}
+
+	tmp := filepath.Join(dir, "..data_tmp")
+	require.NoError(t, os.Symlink(filepath.Base(data), tmp))
+	require.NoError(t, os.Rename(tmp, filepath.Join(dir, "..data")))
+}
+
+func TestDirSource(t *testing.T) {
+	dir := t.TempDir()
+	writeConfigMap(t, dir, "1", map[string]string{
+		"db.password": "secret-v1\n",
+		"http-port":   "8080",
+		".hidden":     "hidden",
+	})
+
+	src := env.DirSource(dir, env.WithKeyMapper(env.EnvKeyName))
+
+	value, ok, err := src.Lookup("DB_PASSWORD")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "secret-v1", value)
+
+	_, ok, err = src.Lookup("_HIDDEN")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	values, err := src.Values()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"DB_PASSWORD": "secret-v1", "HTTP_PORT": "8080"}, values)
+
+	// dotfiles
+	value, ok, err = env.DirSource(dir, env.IncludeDotfiles()).Lookup(".hidden")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "hidden", value)
+
+	// update through the "..data" swap
+	writeConfigMap(t, dir, "2", map[string]string{"db.password": "secret-v2", "http-port": "9090"})
+	env.SetSource(src)
+	defer env.SetSource(nil)
+	assert.Equal(t, "secret-v2", env.MustString("DB_PASSWORD"))
+	assert.Equal(t, 9090, env.MustInt[int]("HTTP_PORT"))
+}
+
+func TestDirSourceLimits(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "LARGE"), []byte(strings.Repeat("x", 11)), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "NESTED"), 0o755))
+
+	_, _, err := env.DirSource(dir, env.WithMaxSize(10)).Lookup("LARGE")
+	assert.Error(t, err)
+
+	_, ok, err := env.DirSource(dir).Lookup("NESTED")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = env.DirSource(filepath.Join(dir, "missing")).Lookup("LARGE")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestEnvKeyName(t *testing.T) {
+	assert.Equal(t, "DB_PASSWORD", env.EnvKeyName("db.password"))
+	assert.Equal(t, "API_KEY_2", env.EnvKeyName("api-key-2"))
+}
@@ -0,0 +1,312 @@
+package env
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DotenvEntry is a variable defined in a dotenv file.
+type DotenvEntry struct {
+	Key   string
+	Value string
+	// Line is the line number where the variable is defined, starting from 1.
+	Line int
+}
+
+// ParseDotenv parses dotenv file contents.
+// Supported syntax:
+//
+//	# comment
+//	KEY=value
+//	export KEY=value
+//	KEY=value # inline comment
+//	KEY="double quoted\nwith escapes"
+//	KEY='single quoted, taken literally'
+//	KEY="multi
+//	line"
+func ParseDotenv(data []byte) ([]DotenvEntry, error) {
+	var entries []DotenvEntry
+
+	src := strings.ReplaceAll(string(data), "\r\n", "\n")
+	line := 0
+	for src != "" {
+		line++
+		var cur string
+		cur, src, _ = strings.Cut(src, "\n")
+
+		s := strings.TrimSpace(cur)
+		// skip empty lines and comments
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+
+		s = strings.TrimPrefix(s, "export ")
+		key, value, ok := strings.Cut(s, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: missing \"=\"", line)
+		}
+		key = strings.TrimSpace(key)
+		if !isValidKey(key) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", line, key)
+		}
+
+		entry := DotenvEntry{Key: key, Line: line}
+		value = strings.TrimLeft(value, " \t")
+
+		if value != "" && (value[0] == '"' || value[0] == '\'') {
+			quote := value[0]
+			// quoted values may span multiple lines
+			rest := value[1:]
+			for {
+				end := closingQuote(rest, quote)
+				if end >= 0 {
+					tail := strings.TrimSpace(rest[end+1:])
+					if tail != "" && !strings.HasPrefix(tail, "#") {
+						return nil, fmt.Errorf("line %d: unexpected characters after the closing quote", line)
+					}
+					rest = rest[:end]
+					break
+				}
+				if src == "" {
+					return nil, fmt.Errorf("line %d: unterminated quoted value", entry.Line)
+				}
+				var next string
+				next, src, _ = strings.Cut(src, "\n")
+				line++
+				rest += "\n" + next
+			}
+
+			if quote == '"' {
+				rest = unescapeDoubleQuoted(rest)
+			}
+			entry.Value = rest
+		} else {
+			// unquoted values end at an inline comment
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = value[:i]
+			}
+			if i := strings.Index(value, "\t#"); i >= 0 {
+				value = value[:i]
+			}
+			entry.Value = strings.TrimSpace(value)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// isValidKey reports whether the key is a valid variable name.
+func isValidKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		if !(c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || i > 0 && (c >= '0' && c <= '9' || c == '.' || c == '-')) {
+			return false
+		}
+	}
+	return true
+}
+
+// closingQuote returns the index of the closing quote in s, or -1 if there is none.
+// Backslash escapes are taken into account for double quotes only.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		if quote == '"' && s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeDoubleQuoted processes escape sequences of a double quoted value.
+func unescapeDoubleQuoted(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '"', '\\', '$':
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// DotenvOption configures how dotenv files are read and loaded.
+type DotenvOption func(*dotenvOptions)
+
+type dotenvOptions struct {
+	override  bool
+	publicKey ed25519.PublicKey
+}
+
+// Override makes LoadDotenv replace variables that are already set in the process environment.
+func Override() DotenvOption {
+	return func(o *dotenvOptions) { o.override = true }
+}
+
+// WithSignature requires dotenv files to be signed with the private key matching the public key.
+// The signature is read from the trailing signature comment or the detached "<file>.sig" file.
+// Files without a valid signature are refused.
+func WithSignature(publicKey ed25519.PublicKey) DotenvOption {
+	return func(o *dotenvOptions) { o.publicKey = publicKey }
+}
+
+// ReadDotenv reads and parses the dotenv file, verifying its signature if required.
+func ReadDotenv(path string, opts ...DotenvOption) ([]DotenvEntry, error) {
+	var o dotenvOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dotenv file: %w", err)
+	}
+
+	if o.publicKey != nil {
+		if err := verifyDotenvFile(path, data, o.publicKey); err != nil {
+			return nil, fmt.Errorf("dotenv file %q: %w", path, err)
+		}
+	}
+
+	entries, err := ParseDotenv(data)
+	if err != nil {
+		return nil, fmt.Errorf("dotenv file %q: %w", path, err)
+	}
+
+	return entries, nil
+}
+
+// LoadDotenv reads the dotenv file and sets its variables in the process environment.
+// Variables which are already set are kept unless the Override option is used.
+func LoadDotenv(path string, opts ...DotenvOption) error {
+	var o dotenvOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	entries, err := ReadDotenv(path, opts...)
+	if err != nil {
+		return err
+	}
+
+	// later definitions of the same key in the file win
+	loaded := make(map[string]bool)
+	for _, e := range entries {
+		if _, exists := os.LookupEnv(e.Key); exists && !o.override && !loaded[e.Key] {
+			continue
+		}
+		if err := os.Setenv(e.Key, e.Value); err != nil {
+			return fmt.Errorf("failed to set ENV %q: %w", e.Key, err)
+		}
+		loaded[e.Key] = true
+	}
+
+	return nil
+}
+
+// DotenvFile is a Source holding the variables of a dotenv file.
+type DotenvFile struct {
+	path string
+	opts []DotenvOption
+
+	mu      sync.RWMutex
+	entries []DotenvEntry
+}
+
+// DotenvSource reads the dotenv file into a Source.
+// The file isn't re-read on lookups, call Reload to pick up changes.
+func DotenvSource(path string, opts ...DotenvOption) (*DotenvFile, error) {
+	f := &DotenvFile{path: path, opts: opts}
+	if err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Path returns the path of the file.
+func (f *DotenvFile) Path() string {
+	return f.path
+}
+
+// Entries returns the entries of the file.
+func (f *DotenvFile) Entries() []DotenvEntry {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.entries
+}
+
+// Reload implements Reloader. On failure the previously read entries are kept.
+func (f *DotenvFile) Reload() error {
+	entries, err := ReadDotenv(f.path, f.opts...)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	f.entries = entries
+	f.mu.Unlock()
+	return nil
+}
+
+// entry returns the last definition of the key.
+func (f *DotenvFile) entry(key string) (DotenvEntry, bool) {
+	entries := f.Entries()
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Key == key {
+			return entries[i], true
+		}
+	}
+	return DotenvEntry{}, false
+}
+
+// Lookup implements Source.
+func (f *DotenvFile) Lookup(key string) (string, bool, error) {
+	e, ok := f.entry(key)
+	return e.Value, ok, nil
+}
+
+// Location implements Locator.
+func (f *DotenvFile) Location(key string) string {
+	if e, ok := f.entry(key); ok {
+		return fmt.Sprintf("%s:%d", f.path, e.Line)
+	}
+	return ""
+}
+
+// Keys implements KeyLister.
+func (f *DotenvFile) Keys() ([]string, error) {
+	entries := f.Entries()
+	keys := make([]string, 0, len(entries))
+	for _, e := range entries {
+		keys = append(keys, e.Key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,59 @@
+package env_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDotenv(t *testing.T) {
+	entries, err := env.ParseDotenv([]byte(`# comment
+HTTP_PORT=8080
+export APP_NAME = demo app  # inline comment
+EMPTY=
+HASH=a#b
+DQ="line1\nline2 \"quoted\" \$HOME" # comment
+SQ='literal \n $HOME'
+MULTI="first
+second"
+db.password=secret
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []env.DotenvEntry{
+		{Key: "HTTP_PORT", Value: "8080", Line: 2},
+		{Key: "APP_NAME", Value: "demo app", Line: 3},
+		{Key: "EMPTY", Value: "", Line: 4},
+		{Key: "HASH", Value: "a#b", Line: 5},
+		{Key: "DQ", Value: "line1\nline2 \"quoted\" $HOME", Line: 6},
+		{Key: "SQ", Value: `literal \n $HOME`, Line: 7},
+		{Key: "MULTI", Value: "first\nsecond", Line: 8},
+		{Key: "db.password", Value: "secret", Line: 10},
+	}, entries)
+
+	for _, data := range []string{"NO_EQUALS", "1KEY=value", "KEY=\"unterminated", "KEY=\"value\" tail"} {
+		_, err = env.ParseDotenv([]byte(data))
+		assert.Error(t, err, data)
+	}
+}
+
+func TestLoadDotenv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("TEST_DOTENV_A=from file\nTEST_DOTENV_B=1\nTEST_DOTENV_B=2\n"), 0o600))
+
+	t.Setenv("TEST_DOTENV_A", "from env")
+	t.Setenv("TEST_DOTENV_B", "")
+	os.Unsetenv("TEST_DOTENV_B")
+
+	assert.NoError(t, env.LoadDotenv(path))
+	assert.Equal(t, "from env", env.MustString("TEST_DOTENV_A"))
+	assert.Equal(t, 2, env.MustInt[int]("TEST_DOTENV_B"))
+
+	assert.NoError(t, env.LoadDotenv(path, env.Override()))
+	assert.Equal(t, "from file", env.MustString("TEST_DOTENV_A"))
+
+	assert.Error(t, env.LoadDotenv(filepath.Join(t.TempDir(), "missing.env")))
+}
@@ -0,0 +1,381 @@
+// Package envtest provides helpers to override environment variables in tests
+// without touching the process environment.
+//
+// Overrides are kept in an in-memory layer installed on top of the current env.Source,
+// so every Get*, Must* and Lookup* function sees them, and they are removed on test cleanup.
+//
+// The overrides are not scoped to a test: the getters of the env package are package-level
+// functions, so every test running at the same time, including parallel tests which only read
+// a variable, sees the overrides of the others. envtest detects the collisions instead:
+// overriding a key which is already overridden by another running test fails the test, and
+// parallel tests reading a key which other tests may override should declare it with Reserve,
+// so a test overriding it while they run fails instead of leaking its value into them.
+// Subtests may shadow the overrides of their parent test. Use replaces the whole underlying
+// source, so it can't run alongside unrelated tests using envtest, which is detected as well.
+// Installing another source with env.SetSource while overrides are active drops them,
+// which fails the tests owning them. The overlay forwards env.KeyLister and env.Reloader
+// to the underlying source, so FindUnknown, Redactor and Watcher keep working.
+//
+//	func TestHandler(t *testing.T) {
+//		t.Parallel()
+//		envtest.Set(t, map[string]string{"HANDLER_TIMEOUT": "1s"})
+//		envtest.Unset(t, "HANDLER_DEBUG")
+//		envtest.Reserve(t, "HANDLER_ADDR") // read from the process environment
+//		...
+//	}
+package envtest
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+)
+
+type override struct {
+	owner string // name of the test which set the override
+	value string
+	unset bool
+}
+
+// overlay is the Source installed on top of the current source.
+type overlay struct {
+	mu        sync.RWMutex
+	base      env.Source
+	overrides map[string][]override // stack per key, the last override wins
+	readers   map[string][]string   // names of the tests which reserved the key
+	users     []string              // names of the tests which replaced the base source with Use
+}
+
+var layer = &overlay{overrides: make(map[string][]override), readers: make(map[string][]string)}
+
+// related reports whether the tests are the same or one is a subtest of the other.
+func related(a, b string) bool {
+	return a == b || strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
+
+// Lookup implements env.Source.
+func (o *overlay) Lookup(key string) (string, bool, error) {
+	o.mu.RLock()
+	stack := o.overrides[key]
+	base := o.base
+	o.mu.RUnlock()
+
+	if len(stack) > 0 {
+		top := stack[len(stack)-1]
+		return top.value, !top.unset, nil
+	}
+	return base.Lookup(key)
+}
+
+// Keys implements env.KeyLister: the keys of the base source, if it implements env.KeyLister,
+// and the overridden keys, without the unset ones.
+func (o *overlay) Keys() ([]string, error) {
+	o.mu.RLock()
+	base := o.base
+	state := make(map[string]bool, len(o.overrides))
+	for key, stack := range o.overrides {
+		state[key] = !stack[len(stack)-1].unset
+	}
+	o.mu.RUnlock()
+
+	var keys []string
+	if lister, ok := base.(env.KeyLister); ok {
+		baseKeys, err := lister.Keys()
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range baseKeys {
+			if _, ok := state[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+	}
+	for key, set := range state {
+		if set {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Reload implements env.Reloader, the base source is reloaded if it implements env.Reloader.
+func (o *overlay) Reload() error {
+	o.mu.RLock()
+	base := o.base
+	o.mu.RUnlock()
+
+	if r, ok := base.(env.Reloader); ok {
+		return r.Reload()
+	}
+	return nil
+}
+
+// checkUsers fails the test if an unrelated running test replaced the base source.
+// The lock must be held.
+func (o *overlay) checkUsers(t testing.TB, name string) {
+	t.Helper()
+	for _, user := range o.users {
+		if !related(name, user) {
+			t.Fatalf("envtest: the source is replaced by the running test %s", user)
+		}
+	}
+}
+
+// install puts the overlay on top of the current source, unless it's already there.
+// The lock must be held.
+func (o *overlay) install() {
+	if current := env.CurrentSource(); current != env.Source(o) {
+		o.base = current
+		env.SetSource(o)
+	}
+}
+
+// set registers the overrides of the test, the lock must not be held.
+func (o *overlay) set(t testing.TB, overrides map[string]override) {
+	t.Helper()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.install()
+
+	name := t.Name()
+	o.checkUsers(t, name)
+	for key := range overrides {
+		stack := o.overrides[key]
+		if len(stack) == 0 {
+			continue
+		}
+		if owner := stack[len(stack)-1].owner; owner != name && !strings.HasPrefix(name, owner+"/") {
+			t.Fatalf("envtest: %s is already overridden by the running test %s", key, owner)
+		}
+	}
+	for key := range overrides {
+		for _, reader := range o.readers[key] {
+			if !related(name, reader) {
+				t.Fatalf("envtest: %s is reserved by the running test %s", key, reader)
+			}
+		}
+	}
+
+	for key, ov := range overrides {
+		ov.owner = name
+		o.overrides[key] = append(o.overrides[key], ov)
+	}
+
+	t.Cleanup(func() {
+		if o.release(name, overrides) {
+			t.Errorf("envtest: the overrides of %s were dropped by env.SetSource", name)
+		}
+	})
+}
+
+// release removes the overrides of the test.
+// It reports whether the overlay was replaced with env.SetSource while the overrides were active.
+func (o *overlay) release(name string, overrides map[string]override) (dropped bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	dropped = env.CurrentSource() != env.Source(o)
+
+	for key := range overrides {
+		stack := o.overrides[key]
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].owner == name {
+				stack = append(stack[:i], stack[i+1:]...)
+				break
+			}
+		}
+		if len(stack) == 0 {
+			delete(o.overrides, key)
+		} else {
+			o.overrides[key] = stack
+		}
+	}
+	return dropped
+}
+
+// reserve registers the test as a reader of the keys, the lock must not be held.
+func (o *overlay) reserve(t testing.TB, keys []string) {
+	t.Helper()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	name := t.Name()
+	o.checkUsers(t, name)
+	for _, key := range keys {
+		for _, ov := range o.overrides[key] {
+			if !related(name, ov.owner) {
+				t.Fatalf("envtest: %s is already overridden by the running test %s", key, ov.owner)
+			}
+		}
+	}
+
+	for _, key := range keys {
+		o.readers[key] = append(o.readers[key], name)
+	}
+
+	t.Cleanup(func() {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		for _, key := range keys {
+			readers := o.readers[key]
+			for i, reader := range readers {
+				if reader == name {
+					readers = append(readers[:i], readers[i+1:]...)
+					break
+				}
+			}
+			if len(readers) == 0 {
+				delete(o.readers, key)
+			} else {
+				o.readers[key] = readers
+			}
+		}
+	})
+}
+
+// Set overrides the variables for the duration of the test.
+func Set(t testing.TB, values map[string]string) {
+	t.Helper()
+
+	overrides := make(map[string]override, len(values))
+	for key, value := range values {
+		overrides[key] = override{value: value}
+	}
+	layer.set(t, overrides)
+}
+
+// Unset hides the variables for the duration of the test, as if they were not set.
+func Unset(t testing.TB, keys ...string) {
+	t.Helper()
+
+	overrides := make(map[string]override, len(keys))
+	for _, key := range keys {
+		overrides[key] = override{unset: true}
+	}
+	layer.set(t, overrides)
+}
+
+// Reserve declares that the test reads the variables as they are, without overriding them.
+// Any number of tests may reserve the same keys, but overriding a reserved key by another
+// running test, or reserving a key overridden by another running test, fails the test.
+// It makes parallel tests reading the keys safe from the overrides of other tests.
+func Reserve(t testing.TB, keys ...string) {
+	t.Helper()
+	layer.reserve(t, keys)
+}
+
+// FromDotenv overrides the variables with the ones defined in the dotenv file
+// for the duration of the test.
+func FromDotenv(t testing.TB, path string, opts ...env.DotenvOption) {
+	t.Helper()
+
+	entries, err := env.ReadDotenv(path, opts...)
+	if err != nil {
+		t.Fatalf("envtest: %v", err)
+	}
+
+	values := make(map[string]string, len(entries))
+	for _, e := range entries {
+		values[e.Key] = e.Value
+	}
+	Set(t, values)
+}
+
+// Use replaces the source under the overrides with src for the duration of the test,
+// e.g. an in-memory Env, so the process environment is not consulted at all.
+// As the source is shared by the whole process, every test running at the same time would
+// read from src: Use fails the test if any unrelated running test has overrides, reservations
+// or its own source, and Set, Unset and Reserve fail in unrelated tests while it's active.
+// Subtests of the test, including parallel ones, may use the source and override it.
+func Use(t testing.TB, src env.Source) {
+	t.Helper()
+
+	layer.mu.Lock()
+	defer layer.mu.Unlock()
+
+	name := t.Name()
+	layer.checkUsers(t, name)
+	for key, stack := range layer.overrides {
+		for _, ov := range stack {
+			if !related(name, ov.owner) {
+				t.Fatalf("envtest: %s is overridden by the running test %s", key, ov.owner)
+			}
+		}
+	}
+	for key, readers := range layer.readers {
+		for _, reader := range readers {
+			if !related(name, reader) {
+				t.Fatalf("envtest: %s is reserved by the running test %s", key, reader)
+			}
+		}
+	}
+
+	layer.install()
+	prev := layer.base
+	layer.base = src
+	layer.users = append(layer.users, name)
+	t.Cleanup(func() {
+		layer.mu.Lock()
+		defer layer.mu.Unlock()
+		layer.base = prev
+		for i, user := range layer.users {
+			if user == name {
+				layer.users = append(layer.users[:i], layer.users[i+1:]...)
+				break
+			}
+		}
+	})
+}
+
+// Env is an in-memory env.Source safe for concurrent use.
+type Env struct {
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// NewEnv creates an in-memory Env with the values.
+func NewEnv(values map[string]string) *Env {
+	e := &Env{values: make(map[string]string, len(values))}
+	for key, value := range values {
+		e.values[key] = value
+	}
+	return e
+}
+
+// Lookup implements env.Source.
+func (e *Env) Lookup(key string) (string, bool, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	value, ok := e.values[key]
+	return value, ok, nil
+}
+
+// Set sets the variable.
+func (e *Env) Set(key, value string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.values[key] = value
+}
+
+// Unset removes the variable.
+func (e *Env) Unset(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.values, key)
+}
+
+// Keys implements env.KeyLister.
+func (e *Env) Keys() ([]string, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	keys := make([]string, 0, len(e.values))
+	for key := range e.values {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
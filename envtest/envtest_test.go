@@ -0,0 +1,225 @@
+package envtest_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/dmitrymomot/go-env/envtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSet(t *testing.T) {
+	for i := 0; i < 4; i++ {
+		i := i
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			t.Parallel()
+			key := fmt.Sprintf("ENVTEST_PARALLEL_%d", i)
+			envtest.Set(t, map[string]string{key: fmt.Sprint(i)})
+			assert.Equal(t, i, env.MustInt[int](key))
+			_, exists := os.LookupEnv(key)
+			assert.False(t, exists)
+		})
+	}
+
+	t.Run("cleanup", func(t *testing.T) {
+		assert.False(t, isSet("ENVTEST_PARALLEL_0"))
+	})
+}
+
+func isSet(key string) bool {
+	_, ok, _ := env.CurrentSource().Lookup(key)
+	return ok
+}
+
+func TestSetShadowing(t *testing.T) {
+	envtest.Set(t, map[string]string{"ENVTEST_SHADOWED": "parent"})
+
+	t.Run("child", func(t *testing.T) {
+		envtest.Set(t, map[string]string{"ENVTEST_SHADOWED": "child"})
+		assert.Equal(t, "child", env.MustString("ENVTEST_SHADOWED"))
+	})
+
+	assert.Equal(t, "parent", env.MustString("ENVTEST_SHADOWED"))
+}
+
+// fakeT records Fatalf calls instead of failing the test.
+type fakeT struct {
+	testing.TB
+	name     string
+	failed   bool
+	cleanups []func()
+}
+
+func (f *fakeT) Name() string                      { return f.name }
+func (f *fakeT) Helper()                           {}
+func (f *fakeT) Cleanup(fn func())                 { f.cleanups = append(f.cleanups, fn) }
+func (f *fakeT) Fatalf(format string, args ...any) { f.failed = true; runtime.Goexit() }
+func (f *fakeT) Errorf(format string, args ...any) { f.failed = true }
+
+func (f *fakeT) run(fn func(testing.TB)) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(f)
+	}()
+	<-done
+}
+
+func (f *fakeT) cleanup() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestSetConflict(t *testing.T) {
+	a := &fakeT{name: "TestConflictA"}
+	b := &fakeT{name: "TestConflictB"}
+
+	a.run(func(t testing.TB) { envtest.Set(t, map[string]string{"ENVTEST_CONFLICT": "a"}) })
+	b.run(func(t testing.TB) { envtest.Set(t, map[string]string{"ENVTEST_CONFLICT": "b"}) })
+	assert.False(t, a.failed)
+	assert.True(t, b.failed)
+	assert.Equal(t, "a", env.MustString("ENVTEST_CONFLICT"))
+
+	a.cleanup()
+	assert.False(t, isSet("ENVTEST_CONFLICT"))
+}
+
+func TestReserve(t *testing.T) {
+	// parallel tests reading the same key
+	t.Run("readers", func(t *testing.T) {
+		for _, name := range []string{"a", "b"} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				envtest.Reserve(t, "ENVTEST_RESERVED")
+				assert.Equal(t, "default", env.GetString("ENVTEST_RESERVED", "default"))
+			})
+		}
+	})
+
+	reader := &fakeT{name: "TestReserveReader"}
+	writer := &fakeT{name: "TestReserveWriter"}
+	reader.run(func(t testing.TB) { envtest.Reserve(t, "ENVTEST_RESERVED") })
+	writer.run(func(t testing.TB) { envtest.Set(t, map[string]string{"ENVTEST_RESERVED": "leaked"}) })
+	assert.False(t, reader.failed)
+	assert.True(t, writer.failed)
+	assert.False(t, isSet("ENVTEST_RESERVED"))
+	reader.cleanup()
+
+	writer = &fakeT{name: "TestReserveWriter"}
+	reader = &fakeT{name: "TestReserveReader"}
+	writer.run(func(t testing.TB) { envtest.Set(t, map[string]string{"ENVTEST_RESERVED": "a"}) })
+	reader.run(func(t testing.TB) { envtest.Reserve(t, "ENVTEST_RESERVED") })
+	assert.False(t, writer.failed)
+	assert.True(t, reader.failed)
+	writer.cleanup()
+}
+
+func TestSetSourceDropsOverrides(t *testing.T) {
+	ft := &fakeT{name: "TestDropped"}
+	ft.run(func(t testing.TB) { envtest.Set(t, map[string]string{"ENVTEST_DROPPED": "a"}) })
+	env.SetSource(nil)
+	assert.False(t, isSet("ENVTEST_DROPPED"))
+	ft.cleanup()
+	assert.True(t, ft.failed)
+}
+
+func TestUnset(t *testing.T) {
+	t.Setenv("ENVTEST_UNSET", "from process")
+	t.Run("unset", func(t *testing.T) {
+		envtest.Unset(t, "ENVTEST_UNSET")
+		assert.False(t, isSet("ENVTEST_UNSET"))
+		assert.Equal(t, "from process", os.Getenv("ENVTEST_UNSET"))
+	})
+	assert.Equal(t, "from process", env.MustString("ENVTEST_UNSET"))
+}
+
+func TestFromDotenv(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("ENVTEST_DOTENV_PORT=8080\n"), 0o600))
+
+	envtest.FromDotenv(t, path)
+	assert.Equal(t, 8080, env.MustInt[int]("ENVTEST_DOTENV_PORT"))
+}
+
+func TestUse(t *testing.T) {
+	t.Setenv("ENVTEST_USE_PROCESS", "1")
+
+	e := envtest.NewEnv(map[string]string{"ENVTEST_USE_HOST": "memory"})
+	t.Run("use", func(t *testing.T) {
+		envtest.Use(t, e)
+		envtest.Set(t, map[string]string{"ENVTEST_USE_PORT": "80"})
+
+		assert.Equal(t, "memory", env.MustString("ENVTEST_USE_HOST"))
+		assert.Equal(t, 80, env.MustInt[int]("ENVTEST_USE_PORT"))
+		assert.False(t, isSet("ENVTEST_USE_PROCESS"))
+
+		e.Set("ENVTEST_USE_HOST", "updated")
+		assert.Equal(t, "updated", env.MustString("ENVTEST_USE_HOST"))
+		e.Unset("ENVTEST_USE_HOST")
+		assert.Panics(t, func() { env.MustString("ENVTEST_USE_HOST") })
+	})
+
+	assert.Equal(t, "1", env.MustString("ENVTEST_USE_PROCESS"))
+}
+
+func TestUseConflict(t *testing.T) {
+	writer := &fakeT{name: "TestUseWriter"}
+	user := &fakeT{name: "TestUseUser"}
+	writer.run(func(t testing.TB) { envtest.Set(t, map[string]string{"ENVTEST_USE_CONFLICT": "a"}) })
+	user.run(func(t testing.TB) { envtest.Use(t, envtest.NewEnv(nil)) })
+	assert.False(t, writer.failed)
+	assert.True(t, user.failed)
+	assert.Equal(t, "a", env.MustString("ENVTEST_USE_CONFLICT"))
+	writer.cleanup()
+
+	user = &fakeT{name: "TestUseUser"}
+	reader := &fakeT{name: "TestUseReader"}
+	child := &fakeT{name: "TestUseUser/child"}
+	user.run(func(t testing.TB) {
+		envtest.Use(t, envtest.NewEnv(map[string]string{"ENVTEST_USE_CONFLICT": "memory"}))
+	})
+	reader.run(func(t testing.TB) { envtest.Reserve(t, "ENVTEST_USE_CONFLICT") })
+	child.run(func(t testing.TB) { envtest.Set(t, map[string]string{"ENVTEST_USE_CHILD": "child"}) })
+	assert.False(t, user.failed)
+	assert.True(t, reader.failed)
+	assert.False(t, child.failed)
+	assert.Equal(t, "memory", env.MustString("ENVTEST_USE_CONFLICT"))
+	child.cleanup()
+	user.cleanup()
+	assert.False(t, isSet("ENVTEST_USE_CONFLICT"))
+}
+
+type reloadingSource struct {
+	env.MapSource
+	reloads int
+}
+
+func (s *reloadingSource) Reload() error {
+	s.reloads++
+	return nil
+}
+
+func TestOverlayForwarding(t *testing.T) {
+	base := &reloadingSource{MapSource: env.MapSource{"ENVTEST_FWD_A": "a", "ENVTEST_FWD_B": "b"}}
+	t.Run("forwarding", func(t *testing.T) {
+		envtest.Use(t, base)
+		envtest.Set(t, map[string]string{"ENVTEST_FWD_C": "c"})
+		envtest.Unset(t, "ENVTEST_FWD_B")
+
+		lister, ok := env.CurrentSource().(env.KeyLister)
+		require.True(t, ok)
+		keys, err := lister.Keys()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ENVTEST_FWD_A", "ENVTEST_FWD_C"}, keys)
+
+		require.NoError(t, env.NewWatcher().Reload())
+		assert.Equal(t, 1, base.reloads)
+	})
+}
@@ -0,0 +1,6 @@
+package env
+
+import "errors"
+
+// ErrNotSet is returned by Lookup* functions when the variable doesn't exist or is not set.
+var ErrNotSet = errors.New("not set")
@@ -0,0 +1,253 @@
+package env
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExecEnv builds the environment of a child process, see ForExec.
+// Operations are applied in the call order, so allow and deny lists filter
+// the variables added before them.
+type ExecEnv struct {
+	values map[string]string
+	errs   []error
+}
+
+// ForExec starts an empty environment for a child process.
+//
+//	cmd := exec.Command("helper")
+//	cmd.Env, err = env.ForExec().
+//		FromCurrent().
+//		Allow("PATH", "HOME", "LANG", "HELPER_*").
+//		StripPrefix("HELPER_").
+//		Set("TIMEOUT", 5*time.Second).
+//		SetSlice("HOSTS", ";", []string{"a", "b"}).
+//		Build()
+func ForExec() *ExecEnv {
+	return &ExecEnv{values: make(map[string]string)}
+}
+
+// FromCurrent adds every variable of the process environment.
+func (b *ExecEnv) FromCurrent() *ExecEnv {
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		b.values[key] = value
+	}
+	return b
+}
+
+// From adds the variables with the keys from the current source, missing ones are skipped.
+func (b *ExecEnv) From(keys ...string) *ExecEnv {
+	for _, key := range keys {
+		value, ok, err := lookupEnv(key)
+		if err != nil {
+			b.errs = append(b.errs, fmt.Errorf("failed to read ENV %q: %w", key, err))
+			continue
+		}
+		if ok {
+			b.values[key] = value
+		}
+	}
+	return b
+}
+
+// Allow removes the variables whose keys match none of the patterns, see path.Match.
+func (b *ExecEnv) Allow(patterns ...string) *ExecEnv {
+	for key := range b.values {
+		if !matchKey(patterns, key) {
+			delete(b.values, key)
+		}
+	}
+	return b
+}
+
+// Deny removes the variables whose keys match any of the patterns, see path.Match.
+func (b *ExecEnv) Deny(patterns ...string) *ExecEnv {
+	for key := range b.values {
+		if matchKey(patterns, key) {
+			delete(b.values, key)
+		}
+	}
+	return b
+}
+
+// StripPrefix removes the prefix from the keys which have it, e.g. "HELPER_PORT" becomes "PORT".
+// Renamed variables replace existing ones.
+func (b *ExecEnv) StripPrefix(prefix string) *ExecEnv {
+	return b.RenamePrefix(prefix, "")
+}
+
+// RenamePrefix replaces the prefix of the keys which have it, e.g. "APP_" to "HELPER_".
+// Renamed variables replace existing ones.
+func (b *ExecEnv) RenamePrefix(from, to string) *ExecEnv {
+	renamed := make(map[string]string)
+	for key, value := range b.values {
+		if rest, ok := strings.CutPrefix(key, from); ok && rest != "" {
+			delete(b.values, key)
+			renamed[to+rest] = value
+		}
+	}
+	for key, value := range renamed {
+		b.values[key] = value
+	}
+	return b
+}
+
+// Rename renames the variable, if it's set.
+func (b *ExecEnv) Rename(from, to string) *ExecEnv {
+	if value, ok := b.values[from]; ok {
+		delete(b.values, from)
+		b.values[to] = value
+	}
+	return b
+}
+
+// Unset removes the variables.
+func (b *ExecEnv) Unset(keys ...string) *ExecEnv {
+	for _, key := range keys {
+		delete(b.values, key)
+	}
+	return b
+}
+
+// Set sets the variable formatted the way Get* functions parse it:
+// durations as "1m30s", times in RFC 3339, slices joined with "," and maps as "key=value" pairs
+// joined with ",". Types implementing encoding.TextMarshaler or fmt.Stringer are formatted with them,
+// Secret values are revealed.
+func (b *ExecEnv) Set(key string, value any) *ExecEnv {
+	return b.set(key, value, ",", "=")
+}
+
+// SetSlice sets the variable with the slice elements joined with sep, default is ",".
+func (b *ExecEnv) SetSlice(key, sep string, value any) *ExecEnv {
+	if sep == "" {
+		sep = ","
+	}
+	return b.set(key, value, sep, "=")
+}
+
+// SetMap sets the variable with the map entries joined with sep, default is ",",
+// key and value are separated with kvSep, default is "=". Entries are sorted by key.
+func (b *ExecEnv) SetMap(key, sep, kvSep string, value any) *ExecEnv {
+	if sep == "" {
+		sep = ","
+	}
+	if kvSep == "" {
+		kvSep = "="
+	}
+	return b.set(key, value, sep, kvSep)
+}
+
+func (b *ExecEnv) set(key string, value any, sep, kvSep string) *ExecEnv {
+	s, err := formatValue(value, sep, kvSep)
+	if err != nil {
+		b.errs = append(b.errs, fmt.Errorf("ENV %q: %w", key, err))
+		return b
+	}
+	b.values[key] = s
+	return b
+}
+
+// Build returns the sorted "KEY=value" list for exec.Cmd.Env.
+// It returns an error if any value couldn't be formatted or any key is invalid.
+func (b *ExecEnv) Build() ([]string, error) {
+	errs := append([]error(nil), b.errs...)
+
+	keys := make([]string, 0, len(b.values))
+	for key := range b.values {
+		if key == "" || strings.ContainsAny(key, "=\x00") {
+			errs = append(errs, fmt.Errorf("invalid ENV name %q", key))
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	sort.Strings(keys)
+	environ := make([]string, 0, len(keys))
+	for _, key := range keys {
+		environ = append(environ, key+"="+b.values[key])
+	}
+	return environ, nil
+}
+
+// formatValue formats the value as a variable value, nil pointers are formatted as an empty string.
+func formatValue(value any, sep, kvSep string) (string, error) {
+	// methods with value receivers panic when called on nil pointers
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return "", nil
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case secretValue:
+		return formatValue(v.reveal(), sep, kvSep)
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case time.Duration:
+		return v.String(), nil
+	case time.Time:
+		return v.Format(time.RFC3339), nil
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		return string(text), err
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits()), nil
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Pointer:
+		return formatValue(rv.Elem().Interface(), sep, kvSep)
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, rv.Len())
+		for i := range parts {
+			s, err := formatValue(rv.Index(i).Interface(), sep, kvSep)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = s
+		}
+		return strings.Join(parts, sep), nil
+	case reflect.Map:
+		parts := make([]string, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			k, err := formatValue(iter.Key().Interface(), sep, kvSep)
+			if err != nil {
+				return "", err
+			}
+			v, err := formatValue(iter.Value().Interface(), sep, kvSep)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, k+kvSep+v)
+		}
+		sort.Strings(parts)
+		return strings.Join(parts, sep), nil
+	}
+
+	return "", fmt.Errorf("unsupported type %T", value)
+}
@@ -0,0 +1,84 @@
+package env_test
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForExec(t *testing.T) {
+	t.Setenv("TEST_EXEC_DB_PASSWORD", "exec-secret")
+	t.Setenv("TEST_EXEC_HELPER_PORT", "8080")
+	t.Setenv("TEST_EXEC_HELPER_NAME", "helper")
+	t.Setenv("TEST_EXEC_HELPER_API_TOKEN", "exec-token")
+
+	version, err := env.ParseVersion("1.2.3")
+	require.NoError(t, err)
+
+	environ, err := env.ForExec().
+		FromCurrent().
+		Allow("TEST_EXEC_*").
+		Deny("*_PASSWORD", "*_TOKEN").
+		StripPrefix("TEST_EXEC_HELPER_").
+		Rename("NAME", "HELPER_NAME").
+		Set("TIMEOUT", 90*time.Second).
+		Set("DEBUG", true).
+		Set("RATIO", 0.25).
+		Set("VERSION", version).
+		Set("PASSWORD", env.NewSecret("exec-revealed")).
+		Set("HOSTS", []string{"a", "b"}).
+		SetSlice("PORTS", ";", []int{80, 443}).
+		SetMap("LIMITS", ",", ":", map[string]int{"b": 2, "a": 1}).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"DEBUG=true",
+		"HELPER_NAME=helper",
+		"HOSTS=a,b",
+		"LIMITS=a:1,b:2",
+		"PASSWORD=exec-revealed",
+		"PORT=8080",
+		"PORTS=80;443",
+		"RATIO=0.25",
+		"TIMEOUT=1m30s",
+		"VERSION=1.2.3",
+	}, environ)
+
+	var ratio *float64
+	environ, err = env.ForExec().
+		Set("VERSION", (*env.Version)(nil)).
+		Set("TIMEOUT", (*time.Duration)(nil)).
+		Set("RATIO", ratio).
+		Set("VERSIONS", []*env.Version{nil, &version}).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"RATIO=", "TIMEOUT=", "VERSION=", "VERSIONS=,1.2.3"}, environ)
+
+	_, err = env.ForExec().Set("BAD", struct{}{}).Build()
+	assert.Error(t, err)
+	_, err = env.ForExec().Set("BAD=KEY", "value").Build()
+	assert.Error(t, err)
+}
+
+func TestForExecCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	t.Setenv("TEST_EXEC_LEAKED", "leaked")
+
+	environ, err := env.ForExec().From("PATH", "TEST_EXEC_MISSING").Set("TEST_EXEC_PASSED", 42).Build()
+	require.NoError(t, err)
+
+	cmd := exec.Command("sh", "-c", "env")
+	cmd.Env = environ
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "TEST_EXEC_PASSED=42")
+	assert.False(t, strings.Contains(string(out), "TEST_EXEC_LEAKED"))
+}
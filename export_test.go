@@ -0,0 +1,9 @@
+package env
+
+// ResetAliasWarnings forgets the reported deprecated aliases, so tests can assert the warning again.
+func ResetAliasWarnings() {
+	warned.Range(func(key, _ any) bool {
+		warned.Delete(key)
+		return true
+	})
+}
@@ -0,0 +1,95 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrCycle is returned when defaults reference each other in a cycle.
+var ErrCycle = errors.New("dependency cycle")
+
+// GetStringFunc func returns environment variable value as a string value,
+// If variable doesn't exist or is not set, returns the result of fallback,
+// which is only called when it's needed.
+func GetStringFunc(key string, fallback func() string) string {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
+		return fallback()
+	}
+
+	return value
+}
+
+// GetFunc func returns environment variable value parsed as T,
+// If variable doesn't exist, is not set or unparsable, returns the result of fallback,
+// which is only called when it's needed.
+// Supported types: string, []byte, bool, integers, unsigned integers, floats,
+// time.Duration and types implementing encoding.TextUnmarshaler.
+func GetFunc[T any](key string, fallback func() T) T {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
+		return fallback()
+	}
+
+	res, err := parseValue[T](value)
+	if err != nil {
+		return fallback()
+	}
+
+	return res
+}
+
+// FirstOf returns the value of the first variable which is set and not empty,
+// or an empty string if there is none.
+//
+//	readDSN := env.GetStringFunc("READ_DSN", func() string { return env.FirstOf("WRITE_DSN", "DATABASE_URL") })
+func FirstOf(keys ...string) string {
+	for _, key := range keys {
+		if value, exists, err := lookupEnv(key); err == nil && exists && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Expand replaces ${KEY} references in s with the values of the variables,
+// missing variables are replaced with an empty string.
+//
+//	publicURL := env.GetStringFunc("PUBLIC_URL", func() string { return env.Expand("http://${HOST}:${PORT}") })
+func Expand(s string) string {
+	res, _ := expandRefs(s, func(key string) (string, error) {
+		value, _, err := lookupEnv(key)
+		return value, err
+	})
+	return res
+}
+
+// expandRefs replaces ${KEY} references in s with the values returned by resolve.
+func expandRefs(s string, resolve func(key string) (string, error)) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated reference in %q", s)
+		}
+
+		key := s[start+2 : start+end]
+		if !isValidKey(key) {
+			return "", fmt.Errorf("invalid reference ${%s}", key)
+		}
+		value, err := resolve(key)
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString(s[:start])
+		b.WriteString(value)
+		s = s[start+end+1:]
+	}
+}
@@ -0,0 +1,91 @@
+package env_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFunc(t *testing.T) {
+	calls := 0
+	fallback := func() string {
+		calls++
+		return "fallback"
+	}
+
+	t.Setenv("TEST_FALLBACK_SET", "value")
+	assert.Equal(t, "value", env.GetStringFunc("TEST_FALLBACK_SET", fallback))
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, "fallback", env.GetStringFunc("TEST_FALLBACK_MISSING", fallback))
+	assert.Equal(t, 1, calls)
+
+	t.Setenv("TEST_FALLBACK_TIMEOUT", "soon")
+	assert.Equal(t, time.Second, env.GetFunc("TEST_FALLBACK_TIMEOUT", func() time.Duration { return time.Second }))
+	t.Setenv("TEST_FALLBACK_TIMEOUT", "2s")
+	assert.Equal(t, 2*time.Second, env.GetFunc("TEST_FALLBACK_TIMEOUT", func() time.Duration { return time.Second }))
+}
+
+func TestFirstOf(t *testing.T) {
+	t.Setenv("TEST_FALLBACK_EMPTY", "")
+	t.Setenv("TEST_FALLBACK_WRITE_DSN", "postgres://write")
+	assert.Equal(t, "postgres://write", env.FirstOf("TEST_FALLBACK_READ_DSN", "TEST_FALLBACK_EMPTY", "TEST_FALLBACK_WRITE_DSN"))
+	assert.Equal(t, "", env.FirstOf("TEST_FALLBACK_READ_DSN"))
+}
+
+func TestExpand(t *testing.T) {
+	t.Setenv("TEST_FALLBACK_HOST", "example.com")
+	t.Setenv("TEST_FALLBACK_PORT", "8080")
+	assert.Equal(t, "http://example.com:8080/", env.Expand("http://${TEST_FALLBACK_HOST}:${TEST_FALLBACK_PORT}/"))
+	assert.Equal(t, "a$b", env.Expand("a$b"))
+}
+
+func TestBindDefaultReferences(t *testing.T) {
+	type config struct {
+		PublicURL string `env:"TEST_FALLBACK_PUBLIC_URL" default:"http://${TEST_FALLBACK_HOST}:${TEST_FALLBACK_PORT}"`
+		ReadDSN   string `env:"TEST_FALLBACK_READ_DSN" default:"${TEST_FALLBACK_WRITE_DSN}"`
+		WriteDSN  string `env:"TEST_FALLBACK_WRITE_DSN" default:"postgres://${TEST_FALLBACK_HOST}/app"`
+		Host      string `env:"TEST_FALLBACK_HOST" default:"localhost"`
+		Port      int    `env:"TEST_FALLBACK_PORT" default:"80"`
+	}
+
+	var cfg config
+	require.NoError(t, env.Bind(&cfg))
+	assert.Equal(t, "http://localhost:80", cfg.PublicURL)
+	assert.Equal(t, "postgres://localhost/app", cfg.ReadDSN)
+	assert.Equal(t, "postgres://localhost/app", cfg.WriteDSN)
+
+	t.Setenv("TEST_FALLBACK_WRITE_DSN", "postgres://primary/app")
+	t.Setenv("TEST_FALLBACK_PORT", "8080")
+	cfg = config{}
+	require.NoError(t, env.Bind(&cfg))
+	assert.Equal(t, "http://localhost:8080", cfg.PublicURL)
+	assert.Equal(t, "postgres://primary/app", cfg.ReadDSN)
+}
+
+func TestBindDefaultCycle(t *testing.T) {
+	type config struct {
+		A string `env:"TEST_FALLBACK_CYCLE_A" default:"${TEST_FALLBACK_CYCLE_B}"`
+		B string `env:"TEST_FALLBACK_CYCLE_B" default:"x${TEST_FALLBACK_CYCLE_A}"`
+		C string `env:"TEST_FALLBACK_CYCLE_C" default:"${TEST_FALLBACK_CYCLE_C"`
+	}
+
+	err := env.Bind(&config{})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, env.ErrCycle))
+	assert.Contains(t, err.Error(), "TEST_FALLBACK_CYCLE_A -> TEST_FALLBACK_CYCLE_B -> TEST_FALLBACK_CYCLE_A")
+	assert.Contains(t, err.Error(), "unterminated reference")
+
+	// the cycle is broken when a variable is set
+	t.Setenv("TEST_FALLBACK_CYCLE_B", "b")
+	type acyclic struct {
+		A string `env:"TEST_FALLBACK_CYCLE_A" default:"${TEST_FALLBACK_CYCLE_B}"`
+		B string `env:"TEST_FALLBACK_CYCLE_B" default:"x${TEST_FALLBACK_CYCLE_A}"`
+	}
+	var cfg acyclic
+	require.NoError(t, env.Bind(&cfg))
+	assert.Equal(t, "b", cfg.A)
+}
@@ -1,7 +1,6 @@
 package env
 
 import (
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -10,8 +9,8 @@ import (
 // GetString func returns environment variable value as a string value,
 // If variable doesn't exist or is not set, returns fallback value
 func GetString(key string, fallback string) string {
-	value, exists := os.LookupEnv(key)
-	if !exists {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists {
 		return fallback
 	}
 	return value
@@ -20,8 +19,8 @@ func GetString(key string, fallback string) string {
 // GetBool func returns environment variable value as a boolean value,
 // If variable doesn't exist or is not set, returns fallback value
 func GetBool(key string, fallback bool) bool {
-	value, exists := os.LookupEnv(key)
-	if !exists || value == "" {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
 		return fallback
 	}
 
@@ -37,8 +36,8 @@ func GetBool(key string, fallback bool) bool {
 // GetInt func returns environment variable value as a integer value,
 // If variable doesn't exist or is not set, returns fallback value
 func GetInt[T int | int16 | int32 | int64](key string, fallback T) T {
-	value, exists := os.LookupEnv(key)
-	if !exists || value == "" {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
 		return fallback
 	}
 
@@ -53,8 +52,8 @@ func GetInt[T int | int16 | int32 | int64](key string, fallback T) T {
 // GetFloat func returns environment variable value as a float value,
 // If variable doesn't exist or is not set, returns fallback value
 func GetFloat[T float32 | float64](key string, fallback T) T {
-	value, exists := os.LookupEnv(key)
-	if !exists || value == "" {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
 		return fallback
 	}
 
@@ -69,8 +68,8 @@ func GetFloat[T float32 | float64](key string, fallback T) T {
 // GetDuration func returns environment variable value as a parsed duration value,
 // If variable doesn't exist, is not set or unparsable, returns fallback value
 func GetDuration(key string, fallback time.Duration) time.Duration {
-	value, exists := os.LookupEnv(key)
-	if !exists || value == "" {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
 		return fallback
 	}
 
@@ -86,8 +85,8 @@ func GetDuration(key string, fallback time.Duration) time.Duration {
 // If variable doesn't exist, is not set or unparsable, returns fallback value.
 // If format is empty, then time.RFC3339 is used.
 func GetTime(key, format string, fallback time.Time) time.Time {
-	value, exists := os.LookupEnv(key)
-	if !exists || value == "" {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
 		return fallback
 	}
 
@@ -106,8 +105,8 @@ func GetTime(key, format string, fallback time.Time) time.Time {
 // GetBytes func returns environment variable value as a bytes slice
 // If variable doesn't exist or is not set, returns fallback value
 func GetBytes(key string, fallback []byte) []byte {
-	value, exists := os.LookupEnv(key)
-	if !exists || value == "" {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
 		return fallback
 	}
 
@@ -117,8 +116,8 @@ func GetBytes(key string, fallback []byte) []byte {
 // GetStrings func returns environment variable value as a string slice
 // If variable doesn't exist or is not set, returns fallback value
 func GetStrings(key string, sep string, fallback []string) []string {
-	value, exists := os.LookupEnv(key)
-	if !exists || value == "" {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
 		return fallback
 	}
 
@@ -142,8 +141,8 @@ func GetStrings(key string, sep string, fallback []string) []string {
 // GetInts func returns environment variable value as a integer slice
 // If variable doesn't exist or is not set, returns fallback value
 func GetInts[T int | int16 | int32 | int64](key string, sep string, fallback []T) []T {
-	value, exists := os.LookupEnv(key)
-	if !exists || value == "" {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
 		return fallback
 	}
 
@@ -177,8 +176,8 @@ func GetInts[T int | int16 | int32 | int64](key string, sep string, fallback []T
 // GetFloats func returns environment variable value as a float slice
 // If variable doesn't exist or is not set, returns fallback value
 func GetFloats[T float32 | float64](key string, sep string, fallback []T) []T {
-	value, exists := os.LookupEnv(key)
-	if !exists || value == "" {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
 		return fallback
 	}
 
@@ -215,8 +214,8 @@ func GetFloats[T float32 | float64](key string, sep string, fallback []T) []T {
 // sep - key value separator, default is ","
 // kvSep - key value separator, default is "="
 func GetStringsMap(key string, sep string, kvSep string, fallback map[string]string) map[string]string {
-	value, exists := os.LookupEnv(key)
-	if !exists || value == "" {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
 		return fallback
 	}
 
@@ -269,8 +268,8 @@ func GetStringsMap(key string, sep string, kvSep string, fallback map[string]str
 // sep - key value separator, default is ","
 // kvSep - key value separator, default is "="
 func GetIntsMap[T int | int16 | int32 | int64](key string, sep string, kvSep string, fallback map[string]T) map[string]T {
-	value, exists := os.LookupEnv(key)
-	if !exists || value == "" {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
 		return fallback
 	}
 
@@ -333,8 +332,8 @@ func GetIntsMap[T int | int16 | int32 | int64](key string, sep string, kvSep str
 // sep - key value separator, default is ","
 // kvSep - key value separator, default is "="
 func GetFloatsMap[T float32 | float64](key string, sep string, kvSep string, fallback map[string]T) map[string]T {
-	value, exists := os.LookupEnv(key)
-	if !exists || value == "" {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
 		return fallback
 	}
 
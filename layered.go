@@ -0,0 +1,298 @@
+package env
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Layer is a named Source of a layered Env.
+type Layer struct {
+	Name   string
+	Source Source
+}
+
+// Locator is implemented by sources which know where a value is defined,
+// e.g. "file:line" for dotenv files.
+type Locator interface {
+	Location(key string) string
+}
+
+// KeyLister is implemented by sources which can enumerate their keys.
+type KeyLister interface {
+	Keys() ([]string, error)
+}
+
+// Env merges layers in the declared order: every layer overrides the layers declared before it.
+// It's a Source itself, so it can be installed with SetSource.
+//
+//	env.SetSource(env.NewEnv(
+//		env.NewLayer("defaults", env.MapSource{"HTTP_PORT": "8080"}),
+//		base,    // env.DotenvLayer(".env")
+//		profile, // env.DotenvLayer(".env.production")
+//		env.DirLayer("/etc/config", env.WithKeyMapper(env.EnvKeyName)),
+//		env.OSLayer("APP_"), // only APP_* variables are dumped
+//		overrides, // env.ArgsLayer(flag.Args())
+//	))
+type Env struct {
+	layers []Layer
+}
+
+// NewEnv creates an Env from the layers, the last layer has the highest precedence.
+func NewEnv(layers ...Layer) *Env {
+	return &Env{layers: layers}
+}
+
+// Layers returns the layers in the declared order.
+func (e *Env) Layers() []Layer {
+	return append([]Layer(nil), e.layers...)
+}
+
+// Lookup implements Source.
+func (e *Env) Lookup(key string) (string, bool, error) {
+	for i := len(e.layers) - 1; i >= 0; i-- {
+		value, ok, err := e.layers[i].Source.Lookup(key)
+		if err != nil {
+			return "", false, fmt.Errorf("layer %q: %w", e.layers[i].Name, err)
+		}
+		if ok {
+			return value, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// Reload implements Reloader, every layer implementing Reloader is reloaded.
+func (e *Env) Reload() error {
+	sources := make([]Source, 0, len(e.layers))
+	for _, l := range e.layers {
+		sources = append(sources, l.Source)
+	}
+	return reloadAll(sources)
+}
+
+// Keys implements KeyLister. Keys of layers which can't enumerate them
+// (e.g. a SourceFunc) are only listed if they are defined by another layer.
+// The "os" layer lists the keys of the process environment, see OSLayer.
+func (e *Env) Keys() ([]string, error) {
+	seen := make(map[string]bool)
+	for _, l := range e.layers {
+		lister, ok := l.Source.(KeyLister)
+		if !ok {
+			continue
+		}
+		keys, err := lister.Keys()
+		if err != nil {
+			return nil, fmt.Errorf("layer %q: %w", l.Name, err)
+		}
+		for _, key := range keys {
+			seen[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Origin describes a value defined by a layer.
+type Origin struct {
+	Layer    string
+	Value    string
+	Location string // e.g. "file:line" for dotenv files, empty if unknown
+}
+
+// String formats the layer name and the location of the value.
+func (o Origin) String() string {
+	if o.Location != "" {
+		return fmt.Sprintf("%s (%s)", o.Layer, o.Location)
+	}
+	return o.Layer
+}
+
+// Explanation describes how the value of a variable was resolved.
+type Explanation struct {
+	Key   string
+	Found bool
+	// Winner is the origin of the resolved value.
+	Winner Origin
+	// Shadowed are the values overridden by the winner, from the highest precedence.
+	Shadowed []Origin
+}
+
+// String formats the explanation, secret values are masked, see maskValue.
+func (x Explanation) String() string {
+	return x.format(maskValue)
+}
+
+// Redact formats the explanation, values are masked with the redactor, see Redactor.MaskValue.
+func (x Explanation) Redact(r *Redactor) string {
+	return x.format(r.MaskValue)
+}
+
+func (x Explanation) format(mask func(key, value string) string) string {
+	if !x.Found {
+		return x.Key + " is not set"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s=%s from %s", x.Key, mask(x.Key, x.Winner.Value), x.Winner)
+	for _, o := range x.Shadowed {
+		fmt.Fprintf(&b, "\n  shadows %s from %s", mask(x.Key, o.Value), o)
+	}
+	return b.String()
+}
+
+// Explain reports which layer the value of the key comes from and which values it shadows.
+func (e *Env) Explain(key string) (Explanation, error) {
+	x := Explanation{Key: key}
+	for i := len(e.layers) - 1; i >= 0; i-- {
+		l := e.layers[i]
+		value, ok, err := l.Source.Lookup(key)
+		if err != nil {
+			return x, fmt.Errorf("layer %q: %w", l.Name, err)
+		}
+		if !ok {
+			continue
+		}
+
+		o := Origin{Layer: l.Name, Value: value}
+		if locator, ok := l.Source.(Locator); ok {
+			o.Location = locator.Location(key)
+		}
+		if x.Found {
+			x.Shadowed = append(x.Shadowed, o)
+		} else {
+			x.Found, x.Winner = true, o
+		}
+	}
+	return x, nil
+}
+
+// ExplainAll explains every key listed by the layers, sorted by key.
+func (e *Env) ExplainAll() ([]Explanation, error) {
+	keys, err := e.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	all := make([]Explanation, 0, len(keys))
+	for _, key := range keys {
+		x, err := e.Explain(key)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, x)
+	}
+	return all, nil
+}
+
+// Dump writes every variable listed by the layers with its provenance to w, secret values are masked,
+// see maskValue. It includes the variables of the process environment, limited to the prefixes
+// passed to OSLayer. Use DumpWith to mask values with the patterns and values of a Redactor.
+func (e *Env) Dump(w io.Writer) error {
+	return e.dump(w, Explanation.String)
+}
+
+// DumpWith is Dump masking values with the redactor, see Redactor.MaskValue.
+func (e *Env) DumpWith(w io.Writer, r *Redactor) error {
+	return e.dump(w, func(x Explanation) string { return x.Redact(r) })
+}
+
+func (e *Env) dump(w io.Writer, format func(Explanation) string) error {
+	all, err := e.ExplainAll()
+	if err != nil {
+		return err
+	}
+	for _, x := range all {
+		if _, err := fmt.Fprintln(w, format(x)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Explain reports where the value of the key comes from.
+// If the current source is not an Env, it's explained as a single layer.
+func Explain(key string) (Explanation, error) {
+	if e, ok := CurrentSource().(*Env); ok {
+		return e.Explain(key)
+	}
+	return NewEnv(Layer{Name: "source", Source: CurrentSource()}).Explain(key)
+}
+
+// maskValue masks the value if the key matches DefaultSecretKeyPatterns
+// or the value contains a registered secret, e.g. a value read with MustSecret.
+func maskValue(key, value string) string {
+	if matchKey(DefaultSecretKeyPatterns, key) {
+		return Redacted
+	}
+	for _, s := range secrets.list() {
+		if strings.Contains(value, s) {
+			return Redacted
+		}
+	}
+	return value
+}
+
+// NewLayer creates a layer from the source.
+func NewLayer(name string, src Source) Layer {
+	return Layer{Name: name, Source: src}
+}
+
+// OSLayer creates the "os" layer reading the process environment.
+// Every variable is read, but if prefixes are passed, only the keys starting with any of them
+// are listed by Keys, so Dump and ExplainAll skip unrelated variables such as PATH or HOME.
+func OSLayer(prefixes ...string) Layer {
+	if len(prefixes) == 0 {
+		return Layer{Name: "os", Source: OS}
+	}
+	return Layer{Name: "os", Source: prefixedOS{prefixes: prefixes}}
+}
+
+// prefixedOS is the process environment listing only the keys with the prefixes.
+type prefixedOS struct {
+	prefixes []string
+}
+
+// Lookup implements Source.
+func (p prefixedOS) Lookup(key string) (string, bool, error) {
+	return OS.Lookup(key)
+}
+
+// Keys implements KeyLister.
+func (p prefixedOS) Keys() ([]string, error) {
+	return environKeys(p.prefixes), nil
+}
+
+// DirLayer creates a layer from the directory, see DirSource.
+func DirLayer(path string, opts ...DirOption) Layer {
+	return Layer{Name: "dir " + path, Source: DirSource(path, opts...)}
+}
+
+// DotenvLayer creates a layer from the dotenv file, see DotenvSource.
+func DotenvLayer(path string, opts ...DotenvOption) (Layer, error) {
+	src, err := DotenvSource(path, opts...)
+	if err != nil {
+		return Layer{}, err
+	}
+	return Layer{Name: filepath.Base(path), Source: src}, nil
+}
+
+// ArgsLayer creates the "args" layer from KEY=VALUE arguments, e.g. command-line overrides.
+func ArgsLayer(args []string) (Layer, error) {
+	values := make(MapSource, len(args))
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || !isValidKey(key) {
+			return Layer{}, fmt.Errorf("override %q must be in the KEY=VALUE format", key)
+		}
+		values[key] = value
+	}
+	return Layer{Name: "args", Source: values}, nil
+}
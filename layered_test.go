@@ -0,0 +1,106 @@
+package env_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnv(t *testing.T) {
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, ".env")
+	profilePath := filepath.Join(dir, ".env.production")
+	require.NoError(t, os.WriteFile(basePath, []byte("TEST_LAYER_PORT=8081\nTEST_LAYER_HOST=base\nTEST_LAYER_PASSWORD=from-base\n"), 0o600))
+	require.NoError(t, os.WriteFile(profilePath, []byte("# production\nTEST_LAYER_PORT=8082\n"), 0o600))
+
+	configDir := filepath.Join(dir, "config")
+	require.NoError(t, os.Mkdir(configDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "test.layer.port"), []byte("8083"), 0o644))
+
+	base, err := env.DotenvLayer(basePath)
+	require.NoError(t, err)
+	profile, err := env.DotenvLayer(profilePath)
+	require.NoError(t, err)
+	args, err := env.ArgsLayer([]string{"TEST_LAYER_DEBUG=true"})
+	require.NoError(t, err)
+
+	e := env.NewEnv(
+		env.NewLayer("defaults", env.MapSource{"TEST_LAYER_PORT": "8080", "TEST_LAYER_DEBUG": "false"}),
+		base,
+		profile,
+		env.DirLayer(configDir, env.WithKeyMapper(env.EnvKeyName)),
+		env.OSLayer("TEST_LAYER_"),
+		args,
+	)
+	env.SetSource(e)
+	defer env.SetSource(nil)
+
+	assert.Equal(t, 8083, env.MustInt[int]("TEST_LAYER_PORT"))
+	assert.Equal(t, "base", env.MustString("TEST_LAYER_HOST"))
+	assert.True(t, env.MustBool("TEST_LAYER_DEBUG"))
+
+	t.Setenv("TEST_LAYER_PORT", "8084")
+	assert.Equal(t, 8084, env.MustInt[int]("TEST_LAYER_PORT"))
+
+	x, err := env.Explain("TEST_LAYER_PORT")
+	require.NoError(t, err)
+	assert.True(t, x.Found)
+	assert.Equal(t, env.Origin{Layer: "os", Value: "8084"}, x.Winner)
+	assert.Equal(t, []env.Origin{
+		{Layer: "dir " + configDir, Value: "8083", Location: filepath.Join(configDir, "test.layer.port")},
+		{Layer: ".env.production", Value: "8082", Location: profilePath + ":2"},
+		{Layer: ".env", Value: "8081", Location: basePath + ":1"},
+		{Layer: "defaults", Value: "8080"},
+	}, x.Shadowed)
+
+	x, err = e.Explain("TEST_LAYER_MISSING")
+	require.NoError(t, err)
+	assert.False(t, x.Found)
+	assert.Equal(t, "TEST_LAYER_MISSING is not set", x.String())
+
+	// variables set only in the process environment are dumped too
+	t.Setenv("TEST_LAYER_OS_ONLY", "os")
+	var dump bytes.Buffer
+	require.NoError(t, e.Dump(&dump))
+	assert.Equal(t, `TEST_LAYER_DEBUG=true from args
+  shadows false from defaults
+TEST_LAYER_HOST=base from .env (`+basePath+`:2)
+TEST_LAYER_OS_ONLY=os from os
+TEST_LAYER_PASSWORD=[REDACTED] from .env (`+basePath+`:3)
+TEST_LAYER_PORT=8084 from os
+  shadows 8083 from dir `+configDir+` (`+filepath.Join(configDir, "test.layer.port")+`)
+  shadows 8082 from .env.production (`+profilePath+`:2)
+  shadows 8081 from .env (`+basePath+`:1)
+  shadows 8080 from defaults
+`, dump.String())
+
+	_, err = env.ArgsLayer([]string{"NO_EQUALS"})
+	assert.Error(t, err)
+}
+
+func TestEnvDumpMasking(t *testing.T) {
+	e := env.NewEnv(env.NewLayer("config", env.MapSource{
+		"AWS_SECRET_ACCESS_KEY": "aws-s3cr3t-value",
+		"DATABASE_URL":          "postgres://app:pa55@db/app",
+		"STRIPE_API_KEY":        "sk_live_123456",
+		"CACHE_HOST":            "cache.internal",
+	}))
+
+	var dump bytes.Buffer
+	require.NoError(t, e.Dump(&dump))
+	assert.Equal(t, `AWS_SECRET_ACCESS_KEY=[REDACTED] from config
+CACHE_HOST=cache.internal from config
+DATABASE_URL=[REDACTED] from config
+STRIPE_API_KEY=[REDACTED] from config
+`, dump.String())
+
+	dump.Reset()
+	require.NoError(t, e.DumpWith(&dump, env.NewRedactor(env.WithKeyPatterns("*_HOST"), env.WithMask("***"))))
+	assert.Contains(t, dump.String(), "CACHE_HOST=*** from config\n")
+	assert.Contains(t, dump.String(), "DATABASE_URL=postgres://app:pa55@db/app from config\n")
+}
@@ -0,0 +1,22 @@
+package env
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+var currentLogger atomic.Pointer[slog.Logger]
+
+// SetLogger sets the logger used for warnings, e.g. deprecated aliases or unknown variables.
+// Passing nil restores slog.Default.
+func SetLogger(l *slog.Logger) {
+	currentLogger.Store(l)
+}
+
+// logger returns the logger set with SetLogger or slog.Default.
+func logger() *slog.Logger {
+	if l := currentLogger.Load(); l != nil {
+		return l
+	}
+	return slog.Default()
+}
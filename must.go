@@ -2,7 +2,6 @@ package env
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
 	"time"
@@ -11,7 +10,10 @@ import (
 // MustString func returns environment variable value as a string value,
 // If variable doesn't exist or is not set, exits from the runtime
 func MustString(key string) string {
-	value, exists := os.LookupEnv(key)
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
 	if !exists || value == "" {
 		panic(fmt.Errorf("required ENV %q is not set", key))
 	}
@@ -22,7 +24,10 @@ func MustString(key string) string {
 // MustBool func returns environment variable value as a boolean value,
 // If variable doesn't exist or is not set, exits from the runtime
 func MustBool(key string) bool {
-	value, exists := os.LookupEnv(key)
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
 	if !exists || value == "" {
 		panic(fmt.Errorf("required ENV %q is not set", key))
 	}
@@ -39,7 +44,10 @@ func MustBool(key string) bool {
 // MustInt func returns environment variable value as an integer value,
 // If variable doesn't exist or is not set, exits from the runtime
 func MustInt[T int | int16 | int32 | int64](key string) T {
-	value, exists := os.LookupEnv(key)
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
 	if !exists || value == "" {
 		panic(fmt.Errorf("required ENV %q is not set", key))
 	}
@@ -55,7 +63,10 @@ func MustInt[T int | int16 | int32 | int64](key string) T {
 // MustFloat func returns environment variable value as a float value,
 // If variable doesn't exist or is not set, exits from the runtime
 func MustFloat[T float32 | float64](key string) T {
-	value, exists := os.LookupEnv(key)
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
 	if !exists || value == "" {
 		panic(fmt.Errorf("required ENV %q is not set", key))
 	}
@@ -71,7 +82,10 @@ func MustFloat[T float32 | float64](key string) T {
 // MustDuration func returns environment variable value as a parsed duration value,
 // If variable doesn't exist, is not set or unparsable, then panics
 func MustDuration(key string) time.Duration {
-	value, exists := os.LookupEnv(key)
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
 	if !exists || value == "" {
 		panic(fmt.Errorf("required ENV %q is not set", key))
 	}
@@ -89,7 +103,10 @@ func MustDuration(key string) time.Duration {
 // If format is empty, then time.RFC3339 is used.
 // See default time formats: https://golang.org/pkg/time/#pkg-constants
 func MustTime(key string, format string) time.Time {
-	value, exists := os.LookupEnv(key)
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
 	if !exists || value == "" {
 		panic(fmt.Errorf("required ENV %q is not set", key))
 	}
@@ -109,7 +126,10 @@ func MustTime(key string, format string) time.Time {
 // MustBytes func returns environment variable value as a bytes slice.
 // If variable doesn't exist or is not set, exits from the runtime.
 func MustBytes(key string) []byte {
-	value, exists := os.LookupEnv(key)
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
 	if !exists || value == "" {
 		panic(fmt.Errorf("required ENV %q is not set", key))
 	}
@@ -120,7 +140,10 @@ func MustBytes(key string) []byte {
 // MustStrings func returns environment variable value as a string slice.
 // If variable doesn't exist or is not set, exits from the runtime.
 func MustStrings(key string, sep string) []string {
-	value, exists := os.LookupEnv(key)
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
 	if !exists || value == "" {
 		panic(fmt.Errorf("required ENV %q is not set", key))
 	}
@@ -145,7 +168,10 @@ func MustStrings(key string, sep string) []string {
 // MustInts func returns environment variable value as an integer slice.
 // If variable doesn't exist or is not set, exits from the runtime.
 func MustInts[T int | int16 | int32 | int64](key string, sep string) []T {
-	value, exists := os.LookupEnv(key)
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
 	if !exists || value == "" {
 		panic(fmt.Errorf("required ENV %q is not set", key))
 	}
@@ -180,7 +206,10 @@ func MustInts[T int | int16 | int32 | int64](key string, sep string) []T {
 // MustFloats func returns environment variable value as a float slice.
 // If variable doesn't exist or is not set, exits from the runtime.
 func MustFloats[T float32 | float64](key string, sep string) []T {
-	value, exists := os.LookupEnv(key)
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
 	if !exists || value == "" {
 		panic(fmt.Errorf("required ENV %q is not set", key))
 	}
@@ -215,7 +244,10 @@ func MustFloats[T float32 | float64](key string, sep string) []T {
 // MustStringsMap func returns environment variable value as a string map.
 // If variable doesn't exist or is not set, exits from the runtime.
 func MustStringsMap(key string, sep string, kvSep string) map[string]string {
-	value, exists := os.LookupEnv(key)
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
 	if !exists || value == "" {
 		panic(fmt.Errorf("required ENV %q is not set", key))
 	}
@@ -269,7 +301,10 @@ func MustStringsMap(key string, sep string, kvSep string) map[string]string {
 // sep - key value separator, default is ","
 // kvSep - key value separator, default is "="
 func MustIntsMap[T int | int16 | int32 | int64](key string, sep string, kvSep string) map[string]T {
-	value, exists := os.LookupEnv(key)
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
 	if !exists || value == "" {
 		panic(fmt.Errorf("required ENV %q is not set", key))
 	}
@@ -333,7 +368,10 @@ func MustIntsMap[T int | int16 | int32 | int64](key string, sep string, kvSep st
 // sep - key value separator, default is ","
 // kvSep - key value separator, default is "="
 func MustFloatsMap[T float32 | float64](key string, sep string, kvSep string) map[string]T {
-	value, exists := os.LookupEnv(key)
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
 	if !exists || value == "" {
 		panic(fmt.Errorf("required ENV %q is not set", key))
 	}
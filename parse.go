@@ -0,0 +1,75 @@
+package env
+
+import (
+	"encoding"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// parseValue parses a string into a value of type T.
+// Supported types: string, []byte, bool, integers, unsigned integers, floats,
+// time.Duration and types implementing encoding.TextUnmarshaler.
+func parseValue[T any](value string) (T, error) {
+	var res T
+	var v any
+	var err error
+
+	switch any(res).(type) {
+	case string:
+		v = value
+	case []byte:
+		v = []byte(value)
+	case bool:
+		v, err = parseBool(value)
+	case time.Duration:
+		v, err = time.ParseDuration(value)
+	case int:
+		v, err = parseNumber[int](value)
+	case int8:
+		v, err = parseNumber[int8](value)
+	case int16:
+		v, err = parseNumber[int16](value)
+	case int32:
+		v, err = parseNumber[int32](value)
+	case int64:
+		v, err = parseNumber[int64](value)
+	case uint:
+		v, err = parseNumber[uint](value)
+	case uint8:
+		v, err = parseNumber[uint8](value)
+	case uint16:
+		v, err = parseNumber[uint16](value)
+	case uint32:
+		v, err = parseNumber[uint32](value)
+	case uint64:
+		v, err = parseNumber[uint64](value)
+	case float32:
+		v, err = parseNumber[float32](value)
+	case float64:
+		v, err = parseNumber[float64](value)
+	default:
+		u, ok := any(&res).(encoding.TextUnmarshaler)
+		if !ok {
+			return res, fmt.Errorf("unsupported type %T", res)
+		}
+		return res, u.UnmarshalText([]byte(value))
+	}
+
+	if err != nil {
+		return res, err
+	}
+
+	return v.(T), nil
+}
+
+// parseBool parses a boolean value the same way as GetBool does.
+func parseBool(value string) (bool, error) {
+	switch value {
+	case "true", "1":
+		return true, nil
+	case "false", "0":
+		return false, nil
+	}
+	return false, strconv.ErrSyntax
+}
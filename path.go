@@ -0,0 +1,300 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PathOption configures how a path value is resolved and validated.
+type PathOption func(*pathOptions)
+
+type pathOptions struct {
+	mustExist  bool
+	isDir      bool
+	isFile     bool
+	readable   bool
+	writable   bool
+	expandHome bool
+	abs        bool
+	base       string
+}
+
+// MustExist requires the path to exist.
+func MustExist() PathOption {
+	return func(o *pathOptions) { o.mustExist = true }
+}
+
+// IsDir requires the path to exist and to be a directory.
+func IsDir() PathOption {
+	return func(o *pathOptions) { o.isDir = true }
+}
+
+// IsFile requires the path to exist and to be a regular file.
+func IsFile() PathOption {
+	return func(o *pathOptions) { o.isFile = true }
+}
+
+// Readable requires the path to exist and to be readable by the current process.
+func Readable() PathOption {
+	return func(o *pathOptions) { o.readable = true }
+}
+
+// Writable requires the path to be writable by the current process.
+// If the path doesn't exist, its parent directory must be writable.
+func Writable() PathOption {
+	return func(o *pathOptions) { o.writable = true }
+}
+
+// ExpandHome replaces a leading "~/" with the current user's home directory.
+func ExpandHome() PathOption {
+	return func(o *pathOptions) { o.expandHome = true }
+}
+
+// RelativeTo resolves a relative path against the base directory.
+func RelativeTo(base string) PathOption {
+	return func(o *pathOptions) { o.base = base }
+}
+
+// Abs converts the path to an absolute one.
+func Abs() PathOption {
+	return func(o *pathOptions) { o.abs = true }
+}
+
+// ParsePath resolves the path according to the options and validates it.
+func ParsePath(value string, opts ...PathOption) (string, error) {
+	var o pathOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	p := value
+	if p == "" {
+		return "", errors.New("path must not be empty")
+	}
+
+	if o.expandHome && (p == "~" || strings.HasPrefix(p, "~/")) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to expand home directory: %w", err)
+		}
+		p = filepath.Join(home, p[1:])
+	}
+	if o.base != "" && !filepath.IsAbs(p) {
+		p = filepath.Join(o.base, p)
+	}
+	if o.abs {
+		res, err := filepath.Abs(p)
+		if err != nil {
+			return "", fmt.Errorf("failed to get absolute path of %q: %w", p, err)
+		}
+		p = res
+	}
+
+	if err := checkPath(p, o); err != nil {
+		return "", err
+	}
+
+	return p, nil
+}
+
+// checkPath validates existence, type and permissions of the path.
+func checkPath(p string, o pathOptions) error {
+	info, err := os.Stat(p)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to stat path %q: %w", p, err)
+		}
+		if o.mustExist || o.isDir || o.isFile || o.readable {
+			return fmt.Errorf("path %q does not exist", p)
+		}
+		if o.writable {
+			dir := filepath.Dir(p)
+			if err := checkDirWritable(dir); err != nil {
+				return fmt.Errorf("path %q is not writable: %w", p, err)
+			}
+		}
+		return nil
+	}
+
+	if o.isDir && !info.IsDir() {
+		return fmt.Errorf("path %q is not a directory", p)
+	}
+	if o.isFile && !info.Mode().IsRegular() {
+		return fmt.Errorf("path %q is not a regular file", p)
+	}
+
+	if o.readable {
+		f, err := os.Open(p)
+		if err != nil {
+			return fmt.Errorf("path %q is not readable: %w", p, err)
+		}
+		f.Close()
+	}
+
+	if o.writable {
+		if info.IsDir() {
+			if err := checkDirWritable(p); err != nil {
+				return fmt.Errorf("path %q is not writable: %w", p, err)
+			}
+		} else {
+			f, err := os.OpenFile(p, os.O_WRONLY, 0)
+			if err != nil {
+				return fmt.Errorf("path %q is not writable: %w", p, err)
+			}
+			f.Close()
+		}
+	}
+
+	return nil
+}
+
+// checkDirWritable checks that a file can be created in the directory.
+func checkDirWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".env-write-check-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// GetPath func returns environment variable value as a resolved and validated path,
+// If variable doesn't exist, is not set or the path doesn't pass the checks, returns fallback value
+func GetPath(key string, fallback string, opts ...PathOption) string {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
+		return fallback
+	}
+
+	res, err := ParsePath(value, opts...)
+	if err != nil {
+		return fallback
+	}
+
+	return res
+}
+
+// MustPath func returns environment variable value as a resolved and validated path,
+// If variable doesn't exist, is not set or the path doesn't pass the checks, then panics
+func MustPath(key string, opts ...PathOption) string {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
+	if !exists || value == "" {
+		panic(fmt.Errorf("required ENV %q is not set", key))
+	}
+
+	res, err := ParsePath(value, opts...)
+	if err != nil {
+		panic(fmt.Errorf("required ENV %q must be a valid path: %v", key, err))
+	}
+
+	return res
+}
+
+// LookupPath func returns environment variable value as a resolved and validated path.
+// If variable doesn't exist or is not set, returns an error wrapping ErrNotSet.
+// If the path doesn't pass the checks, returns a validation error.
+func LookupPath(key string, opts ...PathOption) (string, error) {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to read ENV %q: %w", key, err)
+	}
+	if !exists || value == "" {
+		return "", fmt.Errorf("ENV %q is %w", key, ErrNotSet)
+	}
+
+	res, err := ParsePath(value, opts...)
+	if err != nil {
+		return "", fmt.Errorf("ENV %q must be a valid path: %w", key, err)
+	}
+
+	return res, nil
+}
+
+// ParsePathList splits value by os.PathListSeparator the way PATH is split,
+// then resolves and validates each path according to the options.
+func ParsePathList(value string, opts ...PathOption) ([]string, error) {
+	var paths []string
+	for _, s := range filepath.SplitList(value) {
+		// filter empty strings
+		if s == "" {
+			continue
+		}
+
+		p, err := ParsePath(s, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, p)
+	}
+
+	if len(paths) == 0 {
+		return nil, errors.New("path list must not be empty")
+	}
+
+	return paths, nil
+}
+
+// GetPathList func returns environment variable value as a list of resolved and validated paths,
+// If variable doesn't exist, is not set or any path doesn't pass the checks, returns fallback value
+// Example: /usr/local/bin:/usr/bin (";" separated on Windows)
+func GetPathList(key string, fallback []string, opts ...PathOption) []string {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
+		return fallback
+	}
+
+	res, err := ParsePathList(value, opts...)
+	if err != nil {
+		return fallback
+	}
+
+	return res
+}
+
+// MustPathList func returns environment variable value as a list of resolved and validated paths,
+// If variable doesn't exist, is not set or any path doesn't pass the checks, then panics
+// Example: /usr/local/bin:/usr/bin (";" separated on Windows)
+func MustPathList(key string, opts ...PathOption) []string {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
+	if !exists || value == "" {
+		panic(fmt.Errorf("required ENV %q is not set", key))
+	}
+
+	res, err := ParsePathList(value, opts...)
+	if err != nil {
+		panic(fmt.Errorf("required ENV %q must be a valid path list: %v", key, err))
+	}
+
+	return res
+}
+
+// LookupPathList func returns environment variable value as a list of resolved and validated paths.
+// If variable doesn't exist or is not set, returns an error wrapping ErrNotSet.
+// If any path doesn't pass the checks, returns a validation error.
+func LookupPathList(key string, opts ...PathOption) ([]string, error) {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ENV %q: %w", key, err)
+	}
+	if !exists || value == "" {
+		return nil, fmt.Errorf("ENV %q is %w", key, ErrNotSet)
+	}
+
+	res, err := ParsePathList(value, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("ENV %q must be a valid path list: %w", key, err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,131 @@
+package env_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "cert.pem")
+	require.NoError(t, os.WriteFile(file, []byte("cert"), 0o600))
+	missing := filepath.Join(dir, "missing")
+
+	p, err := env.ParsePath(file, env.MustExist(), env.IsFile(), env.Readable(), env.Writable())
+	assert.NoError(t, err)
+	assert.Equal(t, file, p)
+
+	p, err = env.ParsePath(dir, env.IsDir(), env.Writable())
+	assert.NoError(t, err)
+	assert.Equal(t, dir, p)
+
+	_, err = env.ParsePath(missing, env.MustExist())
+	assert.ErrorContains(t, err, "does not exist")
+	_, err = env.ParsePath(file, env.IsDir())
+	assert.ErrorContains(t, err, "is not a directory")
+	_, err = env.ParsePath(dir, env.IsFile())
+	assert.ErrorContains(t, err, "is not a regular file")
+
+	// missing path is writable if its parent directory is writable
+	p, err = env.ParsePath(missing, env.Writable())
+	assert.NoError(t, err)
+	assert.Equal(t, missing, p)
+	_, err = env.ParsePath(filepath.Join(missing, "file"), env.Writable())
+	assert.Error(t, err)
+
+	p, err = env.ParsePath("cert.pem", env.RelativeTo(dir), env.IsFile())
+	assert.NoError(t, err)
+	assert.Equal(t, file, p)
+
+	p, err = env.ParsePath(file, env.RelativeTo("/other"))
+	assert.NoError(t, err)
+	assert.Equal(t, file, p)
+
+	p, err = env.ParsePath("data", env.Abs())
+	assert.NoError(t, err)
+	assert.True(t, filepath.IsAbs(p))
+	assert.True(t, strings.HasSuffix(p, string(filepath.Separator)+"data"))
+
+	t.Setenv("HOME", dir)
+	p, err = env.ParsePath("~/cert.pem", env.ExpandHome(), env.IsFile())
+	assert.NoError(t, err)
+	assert.Equal(t, file, p)
+	p, err = env.ParsePath("~/cert.pem")
+	assert.NoError(t, err)
+	assert.Equal(t, "~/cert.pem", p)
+}
+
+func TestGetPath(t *testing.T) {
+	dir := t.TempDir()
+	assert.Equal(t, "/fallback", env.GetPath("TEST_PATH", "/fallback", env.IsDir()))
+	t.Setenv("TEST_PATH", dir)
+	assert.Equal(t, dir, env.GetPath("TEST_PATH", "/fallback", env.IsDir()))
+	assert.Equal(t, "/fallback", env.GetPath("TEST_PATH", "/fallback", env.IsFile()))
+}
+
+func TestMustPath(t *testing.T) {
+	dir := t.TempDir()
+	assert.Panics(t, func() { env.MustPath("TEST_ENV_2") })
+	t.Setenv("TEST_ENV", filepath.Join(dir, "missing"))
+	assert.Panics(t, func() { env.MustPath("TEST_ENV", env.MustExist()) })
+
+	t.Setenv("TEST_ENV", dir)
+	assert.Equal(t, dir, env.MustPath("TEST_ENV", env.MustExist(), env.IsDir()))
+}
+
+func TestLookupPath(t *testing.T) {
+	dir := t.TempDir()
+	_, err := env.LookupPath("TEST_ENV_2")
+	assert.True(t, errors.Is(err, env.ErrNotSet))
+	t.Setenv("TEST_ENV", dir)
+	_, err = env.LookupPath("TEST_ENV", env.IsFile())
+	assert.ErrorContains(t, err, "TEST_ENV")
+
+	p, err := env.LookupPath("TEST_ENV", env.IsDir())
+	assert.NoError(t, err)
+	assert.Equal(t, dir, p)
+}
+
+func TestGetPathList(t *testing.T) {
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	sep := string(os.PathListSeparator)
+	fallback := []string{"/fallback"}
+
+	assert.Equal(t, fallback, env.GetPathList("TEST_PATH_LIST", fallback))
+	t.Setenv("TEST_PATH_LIST", dir1+sep+sep+dir2)
+	assert.Equal(t, []string{dir1, dir2}, env.GetPathList("TEST_PATH_LIST", fallback, env.IsDir()))
+	t.Setenv("TEST_PATH_LIST", dir1+sep+filepath.Join(dir2, "missing"))
+	assert.Equal(t, fallback, env.GetPathList("TEST_PATH_LIST", fallback, env.IsDir()))
+}
+
+func TestMustPathList(t *testing.T) {
+	dir := t.TempDir()
+	sep := string(os.PathListSeparator)
+	assert.Panics(t, func() { env.MustPathList("TEST_ENV_2") })
+	t.Setenv("TEST_ENV", sep)
+	assert.Panics(t, func() { env.MustPathList("TEST_ENV") })
+
+	t.Setenv("TEST_ENV", dir+sep+"relative")
+	assert.Equal(t, []string{dir, filepath.Join(dir, "relative")}, env.MustPathList("TEST_ENV", env.RelativeTo(dir)))
+}
+
+func TestLookupPathList(t *testing.T) {
+	dir := t.TempDir()
+	_, err := env.LookupPathList("TEST_ENV_2")
+	assert.True(t, errors.Is(err, env.ErrNotSet))
+	t.Setenv("TEST_ENV", filepath.Join(dir, "missing"))
+	_, err = env.LookupPathList("TEST_ENV", env.MustExist())
+	assert.Error(t, err)
+
+	t.Setenv("TEST_ENV", dir)
+	paths, err := env.LookupPathList("TEST_ENV", env.MustExist())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{dir}, paths)
+}
@@ -0,0 +1,165 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// GetRegexp func returns environment variable value as a compiled regular expression,
+// If variable doesn't exist, is not set or is not a valid pattern, returns fallback value
+func GetRegexp(key string, fallback *regexp.Regexp) *regexp.Regexp {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
+		return fallback
+	}
+
+	res, err := regexp.Compile(value)
+	if err != nil {
+		return fallback
+	}
+
+	return res
+}
+
+// MustRegexp func returns environment variable value as a compiled regular expression,
+// If variable doesn't exist, is not set or is not a valid pattern, then panics
+func MustRegexp(key string) *regexp.Regexp {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
+	if !exists || value == "" {
+		panic(fmt.Errorf("required ENV %q is not set", key))
+	}
+
+	res, err := regexp.Compile(value)
+	if err != nil {
+		panic(fmt.Errorf("required ENV %q must be a valid regular expression but it's %q: %v", key, value, err))
+	}
+
+	return res
+}
+
+// LookupRegexp func returns environment variable value as a compiled regular expression.
+// If variable doesn't exist or is not set, returns an error wrapping ErrNotSet.
+// If variable is not a valid pattern, returns a compilation error.
+func LookupRegexp(key string) (*regexp.Regexp, error) {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ENV %q: %w", key, err)
+	}
+	if !exists || value == "" {
+		return nil, fmt.Errorf("ENV %q is %w", key, ErrNotSet)
+	}
+
+	res, err := regexp.Compile(value)
+	if err != nil {
+		return nil, fmt.Errorf("ENV %q must be a valid regular expression but it's %q: %w", key, value, err)
+	}
+
+	return res, nil
+}
+
+// Globs is a set of shell file name patterns, see path.Match for the syntax.
+type Globs []string
+
+// ParseGlobs splits value by sep and validates each pattern.
+// sep - patterns separator, default is ","
+func ParseGlobs(value string, sep string) (Globs, error) {
+	if sep == "" {
+		sep = ","
+	}
+
+	var globs Globs
+	for _, s := range strings.Split(value, sep) {
+		// filter empty strings
+		if s == "" {
+			continue
+		}
+
+		if _, err := path.Match(s, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", s, err)
+		}
+
+		globs = append(globs, s)
+	}
+
+	if len(globs) == 0 {
+		return nil, errors.New("glob patterns list must not be empty")
+	}
+
+	return globs, nil
+}
+
+// Match reports whether name matches any of the patterns.
+func (g Globs) Match(name string) bool {
+	for _, pattern := range g {
+		// patterns are validated on parsing, so the error can be ignored
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GetGlobs func returns environment variable value as a set of glob patterns,
+// If variable doesn't exist, is not set or contains invalid patterns, returns fallback value
+// Example: /health,/static/*,*.ico
+// sep - patterns separator, default is ","
+func GetGlobs(key string, sep string, fallback Globs) Globs {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
+		return fallback
+	}
+
+	res, err := ParseGlobs(value, sep)
+	if err != nil {
+		return fallback
+	}
+
+	return res
+}
+
+// MustGlobs func returns environment variable value as a set of glob patterns,
+// If variable doesn't exist, is not set or contains invalid patterns, then panics
+// Example: /health,/static/*,*.ico
+// sep - patterns separator, default is ","
+func MustGlobs(key string, sep string) Globs {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
+	if !exists || value == "" {
+		panic(fmt.Errorf("required ENV %q is not set", key))
+	}
+
+	res, err := ParseGlobs(value, sep)
+	if err != nil {
+		panic(fmt.Errorf("required ENV %q must be a list of glob patterns but it's %q: %v", key, value, err))
+	}
+
+	return res
+}
+
+// LookupGlobs func returns environment variable value as a set of glob patterns.
+// If variable doesn't exist or is not set, returns an error wrapping ErrNotSet.
+// If variable contains invalid patterns, returns a validation error.
+func LookupGlobs(key string, sep string) (Globs, error) {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ENV %q: %w", key, err)
+	}
+	if !exists || value == "" {
+		return nil, fmt.Errorf("ENV %q is %w", key, ErrNotSet)
+	}
+
+	res, err := ParseGlobs(value, sep)
+	if err != nil {
+		return nil, fmt.Errorf("ENV %q must be a list of glob patterns but it's %q: %w", key, value, err)
+	}
+
+	return res, nil
+}
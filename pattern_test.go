@@ -0,0 +1,93 @@
+package env_test
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRegexp(t *testing.T) {
+	fallback := regexp.MustCompile(`^default$`)
+	assert.Equal(t, fallback, env.GetRegexp("TEST_REGEXP", fallback))
+	os.Setenv("TEST_REGEXP", `^https://.*\.example\.com$`)
+	re := env.GetRegexp("TEST_REGEXP", fallback)
+	assert.True(t, re.MatchString("https://api.example.com"))
+	os.Setenv("TEST_REGEXP", `^(unclosed`)
+	assert.Equal(t, fallback, env.GetRegexp("TEST_REGEXP", fallback))
+}
+
+func TestMustRegexp(t *testing.T) {
+	assert.Panics(t, func() { env.MustRegexp("TEST_ENV_2") })
+	os.Setenv("TEST_ENV", `[a-`)
+	assert.PanicsWithError(t,
+		"required ENV \"TEST_ENV\" must be a valid regular expression but it's \"[a-\": error parsing regexp: missing closing ]: `[a-`",
+		func() { env.MustRegexp("TEST_ENV") },
+	)
+
+	os.Setenv("TEST_ENV", `^\d+$`)
+	assert.True(t, env.MustRegexp("TEST_ENV").MatchString("123"))
+}
+
+func TestLookupRegexp(t *testing.T) {
+	_, err := env.LookupRegexp("TEST_ENV_2")
+	assert.True(t, errors.Is(err, env.ErrNotSet))
+	os.Setenv("TEST_ENV", `(`)
+	_, err = env.LookupRegexp("TEST_ENV")
+	assert.ErrorContains(t, err, `"("`)
+
+	os.Setenv("TEST_ENV", `a+`)
+	re, err := env.LookupRegexp("TEST_ENV")
+	assert.NoError(t, err)
+	assert.True(t, re.MatchString("aaa"))
+}
+
+func TestParseGlobs(t *testing.T) {
+	g, err := env.ParseGlobs("/health,/static/*,,*.ico", "")
+	assert.NoError(t, err)
+	assert.Equal(t, env.Globs{"/health", "/static/*", "*.ico"}, g)
+	assert.True(t, g.Match("/health"))
+	assert.True(t, g.Match("/static/app.js"))
+	assert.True(t, g.Match("favicon.ico"))
+	assert.False(t, g.Match("/static/js/app.js"))
+	assert.False(t, g.Match("/api"))
+
+	_, err = env.ParseGlobs("/ok,/bad[", ",")
+	assert.ErrorContains(t, err, `"/bad["`)
+	_, err = env.ParseGlobs(",", ",")
+	assert.Error(t, err)
+}
+
+func TestGetGlobs(t *testing.T) {
+	fallback := env.Globs{"*"}
+	assert.Equal(t, fallback, env.GetGlobs("TEST_GLOBS", "", fallback))
+	os.Setenv("TEST_GLOBS", "*.tmp;*.log")
+	assert.Equal(t, env.Globs{"*.tmp", "*.log"}, env.GetGlobs("TEST_GLOBS", ";", fallback))
+	os.Setenv("TEST_GLOBS", "[")
+	assert.Equal(t, fallback, env.GetGlobs("TEST_GLOBS", "", fallback))
+}
+
+func TestMustGlobs(t *testing.T) {
+	assert.Panics(t, func() { env.MustGlobs("TEST_ENV_2", ",") })
+	os.Setenv("TEST_ENV", "*.go,[")
+	assert.Panics(t, func() { env.MustGlobs("TEST_ENV", ",") })
+
+	os.Setenv("TEST_ENV", "*.go")
+	assert.True(t, env.MustGlobs("TEST_ENV", ",").Match("main.go"))
+}
+
+func TestLookupGlobs(t *testing.T) {
+	_, err := env.LookupGlobs("TEST_ENV_2", ",")
+	assert.True(t, errors.Is(err, env.ErrNotSet))
+	os.Setenv("TEST_ENV", "a\\")
+	_, err = env.LookupGlobs("TEST_ENV", ",")
+	assert.Error(t, err)
+
+	os.Setenv("TEST_ENV", "/tmp/*")
+	g, err := env.LookupGlobs("TEST_ENV", ",")
+	assert.NoError(t, err)
+	assert.True(t, g.Match("/tmp/file"))
+}
@@ -0,0 +1,177 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProfileTest is the profile for which ".local" files are skipped,
+// so test runs don't depend on the developer's local overrides.
+const ProfileTest = "test"
+
+// ErrLoadingForbidden is returned when dotenv files are loaded in a forbidden profile.
+var ErrLoadingForbidden = errors.New("loading dotenv files is forbidden")
+
+// ProfileOption configures the profile cascade.
+type ProfileOption func(*profileOptions)
+
+type profileOptions struct {
+	vars      []string
+	profile   string
+	dir       string
+	forbidden []string
+	dotenv    []DotenvOption
+}
+
+// WithProfileVars sets the variables the profile is read from, the first non-empty wins.
+// Default is APP_ENV, then GO_ENV.
+func WithProfileVars(keys ...string) ProfileOption {
+	return func(o *profileOptions) { o.vars = keys }
+}
+
+// WithProfile sets the profile explicitly instead of reading it from the environment.
+func WithProfile(profile string) ProfileOption {
+	return func(o *profileOptions) { o.profile = profile }
+}
+
+// WithDir sets the directory the dotenv files are looked up in, default is the working directory.
+func WithDir(dir string) ProfileOption {
+	return func(o *profileOptions) { o.dir = dir }
+}
+
+// ForbidIn refuses loading dotenv files in the profiles, e.g. "production".
+// Profiles are compared case-insensitively, so "Production" is forbidden as well.
+func ForbidIn(profiles ...string) ProfileOption {
+	return func(o *profileOptions) { o.forbidden = append(o.forbidden, profiles...) }
+}
+
+// WithDotenvOptions sets the options used to read every dotenv file, e.g. WithSignature.
+func WithDotenvOptions(opts ...DotenvOption) ProfileOption {
+	return func(o *profileOptions) { o.dotenv = opts }
+}
+
+// ProfileFile is a candidate file of the profile cascade.
+type ProfileFile struct {
+	Path   string
+	Found  bool
+	Loaded bool
+	// Skipped is the reason the file was not loaded, if any.
+	Skipped string
+}
+
+// ProfileReport describes the result of the profile cascade.
+type ProfileReport struct {
+	Profile string
+	Files   []ProfileFile
+}
+
+// Loaded returns the paths of the loaded files in the load order.
+func (r ProfileReport) Loaded() []string {
+	var paths []string
+	for _, f := range r.Files {
+		if f.Loaded {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
+// LoadProfile loads the profile cascade into the process environment:
+// .env, .env.local, .env.<profile>, .env.<profile>.local, where later files override earlier ones.
+// Variables already set in the process environment are kept. Missing files are skipped,
+// so are ".local" files for the ProfileTest profile. Profiles containing path separators
+// or ".." are rejected, so the files are never read outside the directory.
+//
+//	report, err := env.LoadProfile(env.ForbidIn("production"))
+//	if err != nil && !errors.Is(err, env.ErrLoadingForbidden) {
+//		...
+//	}
+//	log.Println("loaded", report.Loaded())
+func LoadProfile(opts ...ProfileOption) (ProfileReport, error) {
+	layers, report, err := ProfileLayers(opts...)
+	if err != nil {
+		return report, err
+	}
+
+	values := make(map[string]string)
+	for _, l := range layers {
+		for _, e := range l.Source.(*DotenvFile).Entries() {
+			values[e.Key] = e.Value
+		}
+	}
+
+	for key, value := range values {
+		if _, exists := os.LookupEnv(key); exists {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return report, fmt.Errorf("failed to set ENV %q: %w", key, err)
+		}
+	}
+
+	return report, nil
+}
+
+// ProfileLayers reads the profile cascade into layers for NewEnv without touching the process environment.
+// The layers are returned in the load order, see LoadProfile.
+func ProfileLayers(opts ...ProfileOption) ([]Layer, ProfileReport, error) {
+	o := profileOptions{vars: []string{"APP_ENV", "GO_ENV"}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	report := ProfileReport{Profile: o.profile}
+	if report.Profile == "" {
+		for _, key := range o.vars {
+			if value, ok, err := lookupEnv(key); err != nil {
+				return nil, report, err
+			} else if ok && value != "" {
+				report.Profile = value
+				break
+			}
+		}
+	}
+
+	if strings.ContainsAny(report.Profile, `/\`) || strings.Contains(report.Profile, "..") {
+		return nil, report, fmt.Errorf("profile %q must not contain path separators or \"..\"", report.Profile)
+	}
+	for _, p := range o.forbidden {
+		if strings.EqualFold(p, report.Profile) {
+			return nil, report, fmt.Errorf("profile %q: %w", report.Profile, ErrLoadingForbidden)
+		}
+	}
+
+	names := []string{".env", ".env.local"}
+	if report.Profile != "" {
+		names = append(names, ".env."+report.Profile, ".env."+report.Profile+".local")
+	}
+
+	var layers []Layer
+	for _, name := range names {
+		f := ProfileFile{Path: filepath.Join(o.dir, name)}
+		if info, err := os.Stat(f.Path); err == nil && !info.IsDir() {
+			f.Found = true
+		}
+
+		switch {
+		case !f.Found:
+			f.Skipped = "not found"
+		case report.Profile == ProfileTest && filepath.Ext(name) == ".local":
+			f.Skipped = "local files are skipped in the test profile"
+		default:
+			l, err := DotenvLayer(f.Path, o.dotenv...)
+			if err != nil {
+				return nil, report, err
+			}
+			layers = append(layers, l)
+			f.Loaded = true
+		}
+
+		report.Files = append(report.Files, f)
+	}
+
+	return layers, report, nil
+}
@@ -0,0 +1,98 @@
+package env_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProfileFiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		".env":            "TEST_PROFILE_A=base\nTEST_PROFILE_B=base\nTEST_PROFILE_C=base\nTEST_PROFILE_D=base\n",
+		".env.local":      "TEST_PROFILE_B=local\n",
+		".env.test":       "TEST_PROFILE_C=test\n",
+		".env.test.local": "TEST_PROFILE_D=test-local\n",
+		".env.dev":        "TEST_PROFILE_C=dev\n",
+		".env.dev.local":  "TEST_PROFILE_D=dev-local\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	return dir
+}
+
+func unsetProfileVars(t *testing.T) {
+	for _, key := range []string{"TEST_PROFILE_A", "TEST_PROFILE_B", "TEST_PROFILE_C", "TEST_PROFILE_D"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	dir := writeProfileFiles(t)
+	unsetProfileVars(t)
+	t.Setenv("APP_ENV", "dev")
+	t.Setenv("TEST_PROFILE_A", "from env")
+
+	report, err := env.LoadProfile(env.WithDir(dir))
+	require.NoError(t, err)
+	assert.Equal(t, "dev", report.Profile)
+	assert.Equal(t, []string{
+		filepath.Join(dir, ".env"),
+		filepath.Join(dir, ".env.local"),
+		filepath.Join(dir, ".env.dev"),
+		filepath.Join(dir, ".env.dev.local"),
+	}, report.Loaded())
+
+	assert.Equal(t, "from env", env.MustString("TEST_PROFILE_A"))
+	assert.Equal(t, "local", env.MustString("TEST_PROFILE_B"))
+	assert.Equal(t, "dev", env.MustString("TEST_PROFILE_C"))
+	assert.Equal(t, "dev-local", env.MustString("TEST_PROFILE_D"))
+}
+
+func TestLoadProfileTest(t *testing.T) {
+	dir := writeProfileFiles(t)
+	unsetProfileVars(t)
+	t.Setenv("APP_ENV", "")
+	t.Setenv("TEST_PROFILE_ENV", "test")
+
+	report, err := env.LoadProfile(env.WithDir(dir), env.WithProfileVars("TEST_PROFILE_ENV"))
+	require.NoError(t, err)
+	assert.Equal(t, "test", report.Profile)
+	assert.Equal(t, []env.ProfileFile{
+		{Path: filepath.Join(dir, ".env"), Found: true, Loaded: true},
+		{Path: filepath.Join(dir, ".env.local"), Found: true, Skipped: "local files are skipped in the test profile"},
+		{Path: filepath.Join(dir, ".env.test"), Found: true, Loaded: true},
+		{Path: filepath.Join(dir, ".env.test.local"), Found: true, Skipped: "local files are skipped in the test profile"},
+	}, report.Files)
+
+	assert.Equal(t, "base", env.MustString("TEST_PROFILE_B"))
+	assert.Equal(t, "test", env.MustString("TEST_PROFILE_C"))
+	assert.Equal(t, "base", env.MustString("TEST_PROFILE_D"))
+}
+
+func TestProfileLayers(t *testing.T) {
+	dir := writeProfileFiles(t)
+
+	layers, report, err := env.ProfileLayers(env.WithDir(dir), env.WithProfile("staging"))
+	require.NoError(t, err)
+	assert.Len(t, layers, 2)
+	assert.Equal(t, "not found", report.Files[2].Skipped)
+
+	_, _, err = env.ProfileLayers(env.WithDir(dir), env.WithProfile("production"), env.ForbidIn("production"))
+	assert.True(t, errors.Is(err, env.ErrLoadingForbidden))
+	_, _, err = env.ProfileLayers(env.WithDir(dir), env.WithProfile("Production"), env.ForbidIn("production"))
+	assert.True(t, errors.Is(err, env.ErrLoadingForbidden))
+
+	t.Setenv("APP_ENV", "../../etc/app")
+	_, _, err = env.ProfileLayers(env.WithDir(dir))
+	assert.Error(t, err)
+	_, _, err = env.ProfileLayers(env.WithDir(dir), env.WithProfile(`etc\app`))
+	assert.Error(t, err)
+}
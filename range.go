@@ -0,0 +1,348 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// number is a constraint for numeric types supported by ranges.
+type number interface {
+	int | int8 | int16 | int32 | int64 |
+		uint | uint8 | uint16 | uint32 | uint64 |
+		float32 | float64
+}
+
+// Range represents an inclusive numeric range, e.g. "30000-32767" or "0..15".
+type Range[T number] struct {
+	Min T
+	Max T
+}
+
+// ParseRange parses a range value in the "a-b", "a..b" or single value "a" format.
+func ParseRange[T number](value string) (Range[T], error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Range[T]{}, errors.New("range must not be empty")
+	}
+
+	lo, hi, ok := splitRange(value)
+	if !ok {
+		lo, hi = value, value
+	}
+
+	min, err := parseNumber[T](lo)
+	if err != nil {
+		return Range[T]{}, fmt.Errorf("invalid range start: %w", err)
+	}
+	max, err := parseNumber[T](hi)
+	if err != nil {
+		return Range[T]{}, fmt.Errorf("invalid range end: %w", err)
+	}
+
+	if !isFinite(min) || !isFinite(max) {
+		return Range[T]{}, errors.New("range bounds must be finite")
+	}
+	if min > max {
+		return Range[T]{}, errors.New("range start must be less than or equal to range end")
+	}
+
+	return Range[T]{Min: min, Max: max}, nil
+}
+
+// splitRange splits a range value by ".." or "-" separator.
+// A leading minus sign and a minus sign of a float exponent are not treated as separators.
+func splitRange(value string) (string, string, bool) {
+	if lo, hi, ok := strings.Cut(value, ".."); ok {
+		return strings.TrimSpace(lo), strings.TrimSpace(hi), true
+	}
+
+	for i := 1; i < len(value); i++ {
+		if value[i] != '-' {
+			continue
+		}
+		if prev := value[i-1]; prev == 'e' || prev == 'E' {
+			continue
+		}
+		return strings.TrimSpace(value[:i]), strings.TrimSpace(value[i+1:]), true
+	}
+
+	return "", "", false
+}
+
+// parseNumber parses a string as a number of type T.
+func parseNumber[T number](s string) (T, error) {
+	var zero T
+	switch any(zero).(type) {
+	case int, int8, int16, int32, int64:
+		res, err := strconv.ParseInt(s, 10, bitSize(zero))
+		return T(res), err
+	case uint, uint8, uint16, uint32, uint64:
+		res, err := strconv.ParseUint(s, 10, bitSize(zero))
+		return T(res), err
+	default:
+		res, err := strconv.ParseFloat(s, bitSize(zero))
+		return T(res), err
+	}
+}
+
+// bitSize returns the size in bits of a numeric type.
+func bitSize(v any) int {
+	switch v.(type) {
+	case int8, uint8:
+		return 8
+	case int16, uint16:
+		return 16
+	case int32, uint32, float32:
+		return 32
+	default:
+		return 64
+	}
+}
+
+// Contains reports whether v is within the range (inclusive).
+func (r Range[T]) Contains(v T) bool {
+	return v >= r.Min && v <= r.Max
+}
+
+// isFinite reports whether v is neither infinite nor NaN.
+func isFinite[T number](v T) bool {
+	f := float64(v)
+	return !math.IsInf(f, 0) && !math.IsNaN(f)
+}
+
+// steppable reports whether every value of the range can be reached with step 1:
+// bounds of float ranges must be within the precision of integers of T,
+// which is 2^24 for float32 and 2^53 for float64.
+func (r Range[T]) steppable() bool {
+	var limit float64
+	switch any(r.Min).(type) {
+	case float32:
+		limit = 1 << 24
+	case float64:
+		limit = 1 << 53
+	default:
+		return true
+	}
+	return math.Abs(float64(r.Min)) <= limit && math.Abs(float64(r.Max)) <= limit
+}
+
+// Each calls fn for each value of the range from Min to Max with step 1.
+// Iteration stops if fn returns false. Float ranges with non-finite bounds,
+// or bounds beyond 2^24 for float32 and 2^53 for float64, have no values.
+func (r Range[T]) Each(fn func(v T) bool) {
+	if !r.steppable() {
+		return
+	}
+	for v := r.Min; v <= r.Max; v++ {
+		if !fn(v) || v == r.Max {
+			return
+		}
+	}
+}
+
+// Values returns all values of the range from Min to Max with step 1.
+func (r Range[T]) Values() []T {
+	var values []T
+	r.Each(func(v T) bool {
+		values = append(values, v)
+		return true
+	})
+	return values
+}
+
+// String returns the range in the "a-b" format, or "a" if Min equals Max.
+func (r Range[T]) String() string {
+	if r.Min == r.Max {
+		return fmt.Sprint(r.Min)
+	}
+	return fmt.Sprintf("%v-%v", r.Min, r.Max)
+}
+
+// RangeList represents a list of ranges, e.g. "1-3,7,10-12",
+// like in cron or CPU list syntax.
+type RangeList[T number] []Range[T]
+
+// ParseRangeList parses a list of ranges separated by sep.
+// sep - ranges separator, default is ","
+func ParseRangeList[T number](value string, sep string) (RangeList[T], error) {
+	if sep == "" {
+		sep = ","
+	}
+
+	var list RangeList[T]
+	for _, s := range strings.Split(value, sep) {
+		// filter empty strings
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+
+		r, err := ParseRange[T](s)
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, r)
+	}
+
+	if len(list) == 0 {
+		return nil, errors.New("range list must not be empty")
+	}
+
+	return list, nil
+}
+
+// Contains reports whether v is within any range of the list.
+func (l RangeList[T]) Contains(v T) bool {
+	for _, r := range l {
+		if r.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Values expands the list into a sorted set of unique values.
+func (l RangeList[T]) Values() []T {
+	seen := make(map[T]struct{})
+	var values []T
+	for _, r := range l {
+		r.Each(func(v T) bool {
+			if _, ok := seen[v]; !ok {
+				seen[v] = struct{}{}
+				values = append(values, v)
+			}
+			return true
+		})
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	return values
+}
+
+// String returns the list of ranges separated by ",".
+func (l RangeList[T]) String() string {
+	parts := make([]string, len(l))
+	for i, r := range l {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// GetRange func returns environment variable value as a parsed range value,
+// If variable doesn't exist, is not set or unparsable, returns fallback value
+func GetRange[T number](key string, fallback Range[T]) Range[T] {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
+		return fallback
+	}
+
+	res, err := ParseRange[T](value)
+	if err != nil {
+		return fallback
+	}
+
+	return res
+}
+
+// MustRange func returns environment variable value as a parsed range value,
+// If variable doesn't exist, is not set or unparsable, then panics
+func MustRange[T number](key string) Range[T] {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
+	if !exists || value == "" {
+		panic(fmt.Errorf("required ENV %q is not set", key))
+	}
+
+	res, err := ParseRange[T](value)
+	if err != nil {
+		panic(fmt.Errorf("required ENV %q must be a range but it's %q: %v", key, value, err))
+	}
+
+	return res
+}
+
+// LookupRange func returns environment variable value as a parsed range value.
+// If variable doesn't exist or is not set, returns an error wrapping ErrNotSet.
+// If variable is unparsable, returns a parsing error.
+func LookupRange[T number](key string) (Range[T], error) {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		return Range[T]{}, fmt.Errorf("failed to read ENV %q: %w", key, err)
+	}
+	if !exists || value == "" {
+		return Range[T]{}, fmt.Errorf("ENV %q is %w", key, ErrNotSet)
+	}
+
+	res, err := ParseRange[T](value)
+	if err != nil {
+		return Range[T]{}, fmt.Errorf("ENV %q must be a range but it's %q: %w", key, value, err)
+	}
+
+	return res, nil
+}
+
+// GetRangeList func returns environment variable value as a parsed list of ranges,
+// If variable doesn't exist, is not set or unparsable, returns fallback value
+// Example: 1-3,7,10-12
+// sep - ranges separator, default is ","
+func GetRangeList[T number](key string, sep string, fallback RangeList[T]) RangeList[T] {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
+		return fallback
+	}
+
+	res, err := ParseRangeList[T](value, sep)
+	if err != nil {
+		return fallback
+	}
+
+	return res
+}
+
+// MustRangeList func returns environment variable value as a parsed list of ranges,
+// If variable doesn't exist, is not set or unparsable, then panics
+// Example: 1-3,7,10-12
+// sep - ranges separator, default is ","
+func MustRangeList[T number](key string, sep string) RangeList[T] {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
+	if !exists || value == "" {
+		panic(fmt.Errorf("required ENV %q is not set", key))
+	}
+
+	res, err := ParseRangeList[T](value, sep)
+	if err != nil {
+		panic(fmt.Errorf("required ENV %q must be a range list but it's %q: %v", key, value, err))
+	}
+
+	return res
+}
+
+// LookupRangeList func returns environment variable value as a parsed list of ranges.
+// If variable doesn't exist or is not set, returns an error wrapping ErrNotSet.
+// If variable is unparsable, returns a parsing error.
+func LookupRangeList[T number](key string, sep string) (RangeList[T], error) {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ENV %q: %w", key, err)
+	}
+	if !exists || value == "" {
+		return nil, fmt.Errorf("ENV %q is %w", key, ErrNotSet)
+	}
+
+	res, err := ParseRangeList[T](value, sep)
+	if err != nil {
+		return nil, fmt.Errorf("ENV %q must be a range list but it's %q: %w", key, value, err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,154 @@
+package env_test
+
+import (
+	"errors"
+	"math"
+	"os"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRange(t *testing.T) {
+	r, err := env.ParseRange[int]("30000-32767")
+	assert.NoError(t, err)
+	assert.Equal(t, env.Range[int]{Min: 30000, Max: 32767}, r)
+	assert.True(t, r.Contains(30000))
+	assert.True(t, r.Contains(32767))
+	assert.False(t, r.Contains(32768))
+
+	r, err = env.ParseRange[int]("0..3")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3}, r.Values())
+	assert.Equal(t, "0-3", r.String())
+
+	r, err = env.ParseRange[int]("-5--3")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{-5, -4, -3}, r.Values())
+
+	r, err = env.ParseRange[int]("7")
+	assert.NoError(t, err)
+	assert.Equal(t, env.Range[int]{Min: 7, Max: 7}, r)
+	assert.Equal(t, "7", r.String())
+
+	u, err := env.ParseRange[uint8]("250-255")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint8{250, 251, 252, 253, 254, 255}, u.Values())
+
+	_, err = env.ParseRange[float64]("0.5..1e-1")
+	assert.Error(t, err)
+	f, err := env.ParseRange[float64]("1e-3-0.75")
+	assert.NoError(t, err)
+	assert.Equal(t, env.Range[float64]{Min: 0.001, Max: 0.75}, f)
+	assert.True(t, f.Contains(0.5))
+	f, err = env.ParseRange[float64]("0.5-2.75")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0.5, 1.5, 2.5}, f.Values())
+
+	for _, value := range []string{"0-Inf", "-Inf-0", "NaN", "NaN-1"} {
+		_, err = env.ParseRange[float64](value)
+		assert.Error(t, err, value)
+	}
+	assert.Empty(t, env.Range[float64]{Min: 1e17, Max: 1e17 + 64}.Values())
+	assert.Empty(t, env.Range[float32]{Min: 0, Max: float32(math.Inf(1))}.Values())
+	assert.Empty(t, env.RangeList[float64]{{Min: 1e17, Max: 1e17 + 64}}.Values())
+
+	for _, value := range []string{"", "a-b", "5-1", "1-", "-", "1..2..3"} {
+		_, err = env.ParseRange[int](value)
+		assert.Error(t, err, value)
+	}
+	_, err = env.ParseRange[uint8]("0-256")
+	assert.Error(t, err)
+	_, err = env.ParseRange[uint]("-1-2")
+	assert.Error(t, err)
+}
+
+func TestRangeEach(t *testing.T) {
+	var values []int
+	env.Range[int]{Min: 1, Max: 10}.Each(func(v int) bool {
+		values = append(values, v)
+		return v < 3
+	})
+	assert.Equal(t, []int{1, 2, 3}, values)
+}
+
+func TestParseRangeList(t *testing.T) {
+	l, err := env.ParseRangeList[int]("1-3,7,10-12", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 7, 10, 11, 12}, l.Values())
+	assert.True(t, l.Contains(11))
+	assert.False(t, l.Contains(8))
+	assert.Equal(t, "1-3,7,10-12", l.String())
+
+	l, err = env.ParseRangeList[int]("5-6; 1..2; 2-5", ";")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, l.Values())
+
+	_, err = env.ParseRangeList[int](",", "")
+	assert.Error(t, err)
+	_, err = env.ParseRangeList[int]("1-3,x", "")
+	assert.Error(t, err)
+}
+
+func TestGetRange(t *testing.T) {
+	fallback := env.Range[int]{Min: 1, Max: 2}
+	assert.Equal(t, fallback, env.GetRange("TEST_RANGE", fallback))
+	os.Setenv("TEST_RANGE", "30000-32767")
+	assert.Equal(t, env.Range[int]{Min: 30000, Max: 32767}, env.GetRange("TEST_RANGE", fallback))
+	os.Setenv("TEST_RANGE", "10-1")
+	assert.Equal(t, fallback, env.GetRange("TEST_RANGE", fallback))
+}
+
+func TestMustRange(t *testing.T) {
+	assert.Panics(t, func() { env.MustRange[int]("TEST_ENV_2") })
+	os.Setenv("TEST_ENV", "wrong value")
+	assert.Panics(t, func() { env.MustRange[int]("TEST_ENV") })
+
+	os.Setenv("TEST_ENV", "0..15")
+	assert.Equal(t, env.Range[uint16]{Min: 0, Max: 15}, env.MustRange[uint16]("TEST_ENV"))
+}
+
+func TestLookupRange(t *testing.T) {
+	_, err := env.LookupRange[int]("TEST_ENV_2")
+	assert.True(t, errors.Is(err, env.ErrNotSet))
+	os.Setenv("TEST_ENV", "wrong value")
+	_, err = env.LookupRange[int]("TEST_ENV")
+	assert.Error(t, err)
+
+	os.Setenv("TEST_ENV", "1-2")
+	r, err := env.LookupRange[int64]("TEST_ENV")
+	assert.NoError(t, err)
+	assert.Equal(t, env.Range[int64]{Min: 1, Max: 2}, r)
+}
+
+func TestGetRangeList(t *testing.T) {
+	fallback := env.RangeList[int]{{Min: 1, Max: 1}}
+	assert.Equal(t, fallback, env.GetRangeList("TEST_RANGE_LIST", "", fallback))
+	os.Setenv("TEST_RANGE_LIST", "1-3,7")
+	assert.Equal(t, env.RangeList[int]{{Min: 1, Max: 3}, {Min: 7, Max: 7}}, env.GetRangeList("TEST_RANGE_LIST", "", fallback))
+	os.Setenv("TEST_RANGE_LIST", "1-3,seven")
+	assert.Equal(t, fallback, env.GetRangeList("TEST_RANGE_LIST", "", fallback))
+}
+
+func TestMustRangeList(t *testing.T) {
+	assert.Panics(t, func() { env.MustRangeList[int]("TEST_ENV_2", ",") })
+	os.Setenv("TEST_ENV", ",")
+	assert.Panics(t, func() { env.MustRangeList[int]("TEST_ENV", ",") })
+
+	os.Setenv("TEST_ENV", "0-1,4")
+	assert.Equal(t, []int{0, 1, 4}, env.MustRangeList[int]("TEST_ENV", ",").Values())
+}
+
+func TestLookupRangeList(t *testing.T) {
+	_, err := env.LookupRangeList[int]("TEST_ENV_2", ",")
+	assert.True(t, errors.Is(err, env.ErrNotSet))
+	os.Setenv("TEST_ENV", "3-1")
+	_, err = env.LookupRangeList[int]("TEST_ENV", ",")
+	assert.Error(t, err)
+
+	os.Setenv("TEST_ENV", "2,4-5")
+	l, err := env.LookupRangeList[int]("TEST_ENV", ",")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 4, 5}, l.Values())
+}
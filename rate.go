@@ -0,0 +1,159 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Rate represents a number of events allowed per period of time,
+// e.g. "100/s" or "5/1m".
+type Rate struct {
+	Events int
+	Per    time.Duration
+}
+
+// rateUnits maps human readable unit names to durations.
+// Units supported by time.ParseDuration (ns, us, ms, s, m, h) are handled separately.
+var rateUnits = map[string]time.Duration{
+	"sec":    time.Second,
+	"second": time.Second,
+	"min":    time.Minute,
+	"minute": time.Minute,
+	"hour":   time.Hour,
+	"d":      24 * time.Hour,
+	"day":    24 * time.Hour,
+}
+
+// ParseRate parses a rate value in the "N/unit" or "N/duration" format,
+// e.g. "100/s", "10/min", "5/1m", "1000/1h30m".
+func ParseRate(value string) (Rate, error) {
+	events, per, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return Rate{}, errors.New("rate must be in the N/unit or N/duration format")
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(events))
+	if err != nil || n <= 0 {
+		return Rate{}, errors.New("rate must have a positive number of events")
+	}
+
+	d, err := parseRatePeriod(strings.TrimSpace(per))
+	if err != nil || d <= 0 {
+		return Rate{}, errors.New("rate must have a positive period")
+	}
+
+	return Rate{Events: n, Per: d}, nil
+}
+
+// parseRatePeriod parses the period part of a rate: either a unit name or a duration.
+func parseRatePeriod(per string) (time.Duration, error) {
+	if d, ok := rateUnits[strings.ToLower(per)]; ok {
+		return d, nil
+	}
+	if isLetters(per) {
+		// bare unit, e.g. "s" or "ms"
+		per = "1" + per
+	}
+	return time.ParseDuration(per)
+}
+
+// isLetters reports whether s is not empty and consists of letters only.
+func isLetters(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// PerSecond returns the number of events per second.
+func (r Rate) PerSecond() float64 {
+	if r.Per <= 0 {
+		return 0
+	}
+	return float64(r.Events) / r.Per.Seconds()
+}
+
+// Interval returns the minimum time interval between two events.
+func (r Rate) Interval() time.Duration {
+	if r.Events <= 0 {
+		return 0
+	}
+	return r.Per / time.Duration(r.Events)
+}
+
+// String returns the rate in the "N/unit" or "N/duration" format.
+func (r Rate) String() string {
+	switch r.Per {
+	case time.Second:
+		return fmt.Sprintf("%d/s", r.Events)
+	case time.Minute:
+		return fmt.Sprintf("%d/m", r.Events)
+	case time.Hour:
+		return fmt.Sprintf("%d/h", r.Events)
+	}
+	return fmt.Sprintf("%d/%s", r.Events, r.Per)
+}
+
+// GetRate func returns environment variable value as a parsed rate value,
+// If variable doesn't exist, is not set or unparsable, returns fallback value
+func GetRate(key string, fallback Rate) Rate {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
+		return fallback
+	}
+
+	res, err := ParseRate(value)
+	if err != nil {
+		return fallback
+	}
+
+	return res
+}
+
+// MustRate func returns environment variable value as a parsed rate value,
+// If variable doesn't exist, is not set or unparsable, then panics
+func MustRate(key string) Rate {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
+	if !exists || value == "" {
+		panic(fmt.Errorf("required ENV %q is not set", key))
+	}
+
+	res, err := ParseRate(value)
+	if err != nil {
+		panic(fmt.Errorf("required ENV %q must be a parsable rate but it's %q: %v", key, value, err))
+	}
+
+	return res
+}
+
+// LookupRate func returns environment variable value as a parsed rate value.
+// If variable doesn't exist or is not set, returns an error wrapping ErrNotSet.
+// If variable is unparsable, returns a parsing error.
+func LookupRate(key string) (Rate, error) {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		return Rate{}, fmt.Errorf("failed to read ENV %q: %w", key, err)
+	}
+	if !exists || value == "" {
+		return Rate{}, fmt.Errorf("ENV %q is %w", key, ErrNotSet)
+	}
+
+	res, err := ParseRate(value)
+	if err != nil {
+		return Rate{}, fmt.Errorf("ENV %q must be a parsable rate but it's %q: %w", key, value, err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,76 @@
+package env_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRate(t *testing.T) {
+	r, err := env.ParseRate("100/s")
+	assert.NoError(t, err)
+	assert.Equal(t, env.Rate{Events: 100, Per: time.Second}, r)
+	assert.Equal(t, float64(100), r.PerSecond())
+	assert.Equal(t, 10*time.Millisecond, r.Interval())
+	assert.Equal(t, "100/s", r.String())
+
+	r, err = env.ParseRate("5/1m")
+	assert.NoError(t, err)
+	assert.Equal(t, env.Rate{Events: 5, Per: time.Minute}, r)
+	assert.Equal(t, 12*time.Second, r.Interval())
+	assert.Equal(t, "5/m", r.String())
+
+	r, err = env.ParseRate("10/min")
+	assert.NoError(t, err)
+	assert.Equal(t, env.Rate{Events: 10, Per: time.Minute}, r)
+
+	r, err = env.ParseRate("3/90s")
+	assert.NoError(t, err)
+	assert.Equal(t, env.Rate{Events: 3, Per: 90 * time.Second}, r)
+	assert.Equal(t, "3/1m30s", r.String())
+
+	r, err = env.ParseRate("10/.5s")
+	assert.NoError(t, err)
+	assert.Equal(t, env.Rate{Events: 10, Per: 500 * time.Millisecond}, r)
+
+	for _, value := range []string{"", "100", "0/s", "-1/s", "ten/s", "10/", "10/0s", "10/parsec", "10/-s", "10/s5"} {
+		_, err = env.ParseRate(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestGetRate(t *testing.T) {
+	fallback := env.Rate{Events: 1, Per: time.Second}
+	assert.Equal(t, fallback, env.GetRate("TEST_RATE", fallback))
+	os.Setenv("TEST_RATE", "100/s")
+	assert.Equal(t, env.Rate{Events: 100, Per: time.Second}, env.GetRate("TEST_RATE", fallback))
+	os.Setenv("TEST_RATE", "wrong value")
+	assert.Equal(t, fallback, env.GetRate("TEST_RATE", fallback))
+}
+
+func TestMustRate(t *testing.T) {
+	assert.Panics(t, func() { env.MustRate("TEST_ENV_2") })
+	os.Setenv("TEST_ENV", "wrong value")
+	assert.Panics(t, func() { env.MustRate("TEST_ENV") })
+
+	os.Setenv("TEST_ENV", "5/1m")
+	assert.Equal(t, env.Rate{Events: 5, Per: time.Minute}, env.MustRate("TEST_ENV"))
+}
+
+func TestLookupRate(t *testing.T) {
+	_, err := env.LookupRate("TEST_ENV_2")
+	assert.True(t, errors.Is(err, env.ErrNotSet))
+	os.Setenv("TEST_ENV", "wrong value")
+	_, err = env.LookupRate("TEST_ENV")
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, env.ErrNotSet))
+
+	os.Setenv("TEST_ENV", "20/h")
+	r, err := env.LookupRate("TEST_ENV")
+	assert.NoError(t, err)
+	assert.Equal(t, env.Rate{Events: 20, Per: time.Hour}, r)
+}
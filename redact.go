@@ -0,0 +1,393 @@
+package env
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultSecretKeyPatterns is the list of key patterns used by NewRedactor
+// when no patterns are configured, see path.Match for the syntax.
+// Words such as SECRET or TOKEN match anywhere in the key, e.g. AWS_SECRET_ACCESS_KEY.
+var DefaultSecretKeyPatterns = []string{
+	"*SECRET*", "*TOKEN*", "*PASSWORD*", "*PASSWD*", "*CREDENTIAL*", "*PRIVATE*",
+	"*_KEY", "*_KEY_*", "*_DSN", "*DATABASE_URL*",
+}
+
+// secrets is the registry of values read through the secret getters.
+var secrets = &secretRegistry{values: make(map[string]struct{})}
+
+type secretRegistry struct {
+	mu     sync.RWMutex
+	values map[string]struct{}
+	gen    uint64
+}
+
+// minSecretLen is the minimum length of registered secret values:
+// masking shorter values, e.g. "1" or "on", would corrupt unrelated output.
+const minSecretLen = 6
+
+// add registers secret values, so every Redactor masks them.
+// Values shorter than minSecretLen are ignored.
+func (s *secretRegistry) add(values ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, v := range values {
+		if len(v) < minSecretLen {
+			continue
+		}
+		if _, ok := s.values[v]; !ok {
+			s.values[v] = struct{}{}
+			s.gen++
+		}
+	}
+}
+
+// generation returns a counter that changes every time a new value is registered.
+func (s *secretRegistry) generation() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.gen
+}
+
+func (s *secretRegistry) list() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	res := make([]string, 0, len(s.values))
+	for v := range s.values {
+		res = append(res, v)
+	}
+	return res
+}
+
+// Redactor replaces secret values in output with a mask.
+// It knows every value read through the secret getters (GetSecret, MustSecret, ...),
+// values of environment variables with keys matching the configured patterns
+// and values added explicitly with Add. Read and matched values shorter than
+// 6 bytes are not masked, as they would corrupt unrelated output.
+type Redactor struct {
+	mask     string
+	patterns []string
+
+	mu       sync.RWMutex
+	extra    map[string]struct{}
+	matched  []string
+	values   []string // all known values, longest first
+	gen      uint64
+	modified bool
+}
+
+// RedactorOption configures a Redactor.
+type RedactorOption func(*Redactor)
+
+// WithMask sets the replacement for secret values, default is Redacted.
+func WithMask(mask string) RedactorOption {
+	return func(r *Redactor) { r.mask = mask }
+}
+
+// WithKeyPatterns sets patterns of keys whose values must be redacted,
+// e.g. "*_TOKEN", "*_PASSWORD". Default is DefaultSecretKeyPatterns.
+func WithKeyPatterns(patterns ...string) RedactorOption {
+	return func(r *Redactor) { r.patterns = patterns }
+}
+
+// NewRedactor creates a new Redactor and collects values of the environment variables
+// with keys matching the configured patterns, see Refresh.
+func NewRedactor(opts ...RedactorOption) *Redactor {
+	r := &Redactor{
+		mask:     Redacted,
+		patterns: DefaultSecretKeyPatterns,
+		extra:    make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.Refresh()
+	return r
+}
+
+// Add registers additional values to redact.
+func (r *Redactor) Add(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, v := range values {
+		if v != "" {
+			r.extra[v] = struct{}{}
+		}
+	}
+	r.modified = true
+}
+
+// Refresh re-collects values of the environment variables with keys matching the patterns,
+// from the process environment and from the current source if it implements KeyLister,
+// e.g. a layered Env with dotenv layers or a Chain with a Remote source.
+func (r *Redactor) Refresh() {
+	var matched []string
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if len(value) >= minSecretLen && matchKey(r.patterns, key) {
+			matched = append(matched, value)
+		}
+	}
+
+	src := CurrentSource()
+	if lister, ok := src.(KeyLister); ok {
+		keys, _ := lister.Keys()
+		for _, key := range keys {
+			if !matchKey(r.patterns, key) {
+				continue
+			}
+			if value, ok, err := src.Lookup(key); err == nil && ok && len(value) >= minSecretLen {
+				matched = append(matched, value)
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.matched = matched
+	r.modified = true
+}
+
+// MaskValue returns the mask if the key matches the configured patterns
+// or the value contains a known secret value, and the value as is otherwise.
+func (r *Redactor) MaskValue(key, value string) string {
+	if matchKey(r.patterns, key) {
+		return r.mask
+	}
+	for _, s := range r.secretValues() {
+		if strings.Contains(value, s) {
+			return r.mask
+		}
+	}
+	return value
+}
+
+// matchKey reports whether the key matches any of the patterns.
+func matchKey(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// secretValues returns all known secret values sorted by length, longest first,
+// so a longer value is masked as a whole even if it contains a shorter one.
+func (r *Redactor) secretValues() []string {
+	gen := secrets.generation()
+
+	r.mu.RLock()
+	if !r.modified && r.gen == gen {
+		defer r.mu.RUnlock()
+		return r.values
+	}
+	r.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]struct{})
+	var values []string
+	for _, group := range [][]string{secrets.list(), r.matched} {
+		for _, v := range group {
+			if _, ok := seen[v]; !ok {
+				seen[v] = struct{}{}
+				values = append(values, v)
+			}
+		}
+	}
+	for v := range r.extra {
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			values = append(values, v)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	r.values = values
+	r.gen = gen
+	r.modified = false
+
+	return values
+}
+
+// Redact replaces every occurrence of the secret values in s with the mask.
+func (r *Redactor) Redact(s string) string {
+	out, _ := r.redact([]byte(s), true)
+	return string(out)
+}
+
+// redact replaces secret values in buf with the mask.
+// If final is false, the trailing bytes which may be the beginning of a secret value
+// are not processed and returned as the rest to be prepended to the next chunk.
+func (r *Redactor) redact(buf []byte, final bool) (out, rest []byte) {
+	values := r.secretValues()
+	if len(values) == 0 {
+		return buf, nil
+	}
+
+	secretBytes := make([][]byte, len(values))
+	for i, v := range values {
+		secretBytes[i] = []byte(v)
+	}
+
+	out = make([]byte, 0, len(buf))
+	mask := []byte(r.mask)
+
+scan:
+	for i := 0; i < len(buf); {
+		for _, v := range secretBytes {
+			if bytes.HasPrefix(buf[i:], v) {
+				out = append(out, mask...)
+				i += len(v)
+				continue scan
+			}
+		}
+		if !final {
+			for _, v := range secretBytes {
+				if len(buf)-i < len(v) && bytes.HasPrefix(v, buf[i:]) {
+					// possibly a secret split across writes, wait for more data
+					return out, buf[i:]
+				}
+			}
+		}
+		out = append(out, buf[i])
+		i++
+	}
+
+	return out, nil
+}
+
+// Writer wraps w, so every secret value written through it is replaced with the mask.
+// Values split across Write calls are detected: the trailing bytes which may be
+// the beginning of a secret value are held back until the next Write, Flush or Close.
+func (r *Redactor) Writer(w io.Writer) *RedactingWriter {
+	return &RedactingWriter{redactor: r, w: w}
+}
+
+// RedactingWriter is an io.Writer which masks secret values, see Redactor.Writer.
+type RedactingWriter struct {
+	redactor *Redactor
+	w        io.Writer
+
+	mu      sync.Mutex
+	pending []byte
+}
+
+// Write implements io.Writer.
+func (w *RedactingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	out, rest := w.redactor.redact(append(w.pending, p...), false)
+	w.pending = append([]byte(nil), rest...)
+	if len(out) > 0 {
+		if _, err := w.w.Write(out); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the held back bytes to the underlying writer.
+func (w *RedactingWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) == 0 {
+		return nil
+	}
+
+	out, _ := w.redactor.redact(w.pending, true)
+	w.pending = nil
+	_, err := w.w.Write(out)
+	return err
+}
+
+// Close flushes the held back bytes and closes the underlying writer if it's an io.Closer.
+func (w *RedactingWriter) Close() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if c, ok := w.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Handler wraps h, so secret values are masked in log messages and attributes.
+func (r *Redactor) Handler(h slog.Handler) slog.Handler {
+	return &redactingHandler{redactor: r, handler: h}
+}
+
+type redactingHandler struct {
+	redactor *Redactor
+	handler  slog.Handler
+}
+
+// Enabled implements slog.Handler.
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *redactingHandler) Handle(ctx context.Context, rec slog.Record) error {
+	res := slog.NewRecord(rec.Time, rec.Level, h.redactor.Redact(rec.Message), rec.PC)
+	rec.Attrs(func(a slog.Attr) bool {
+		res.AddAttrs(h.redactor.redactAttr(a))
+		return true
+	})
+	return h.handler.Handle(ctx, res)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactor.redactAttr(a)
+	}
+	return &redactingHandler{redactor: h.redactor, handler: h.handler.WithAttrs(redacted)}
+}
+
+// WithGroup implements slog.Handler.
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{redactor: h.redactor, handler: h.handler.WithGroup(name)}
+}
+
+// redactAttr masks secret values in the attribute value.
+func (r *Redactor) redactAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		a.Value = slog.StringValue(r.Redact(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			attrs[i] = r.redactAttr(ga)
+		}
+		a.Value = slog.GroupValue(attrs...)
+	case slog.KindAny:
+		// errors, stringers and other values are printed by the handler,
+		// so they are checked in their printed form
+		s := fmt.Sprint(v.Any())
+		if rs := r.Redact(s); rs != s {
+			a.Value = slog.StringValue(rs)
+		} else {
+			a.Value = v
+		}
+	default:
+		a.Value = v
+	}
+	return a
+}
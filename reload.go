@@ -0,0 +1,126 @@
+package env
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// FieldChange is a changed field of a reloaded config, secret values are masked.
+type FieldChange struct {
+	Field string
+	Key   string
+	Old   string
+	New   string
+}
+
+// String formats the change.
+func (c FieldChange) String() string {
+	return fmt.Sprintf("%s (%s): %s -> %s", c.Field, c.Key, c.Old, c.New)
+}
+
+// ReloadEvent describes a published config reload.
+type ReloadEvent struct {
+	Changes []FieldChange
+}
+
+// String formats the changes, one per line.
+func (e ReloadEvent) String() string {
+	lines := make([]string, 0, len(e.Changes))
+	for _, c := range e.Changes {
+		lines = append(lines, c.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Reloadable holds a bound config struct which is swapped atomically on reload.
+// On every reload a new struct is bound and validated with Bind, and it's published
+// only if binding and validation succeed and no field tagged reload:"false" has changed.
+// Otherwise the reload is refused, the current config is kept and the reason is returned,
+// so the watcher's error handler logs it. Fields whose variables were scrubbed
+// from the process environment keep their values, see ConsumeOnRead.
+//
+//	type Config struct {
+//		Timeout time.Duration `env:"HTTP_TIMEOUT" default:"5s"`
+//		Port    int           `env:"HTTP_PORT" default:"8080" reload:"false"`
+//	}
+//	cfg, err := env.NewReloadable[Config](env.DefaultWatcher, func(e env.ReloadEvent) {
+//		log.Println("config reloaded:", e)
+//	})
+//	...
+//	client.Timeout = cfg.Load().Timeout
+type Reloadable[T any] struct {
+	onReload func(ReloadEvent)
+	value    atomic.Pointer[T]
+	mu       sync.Mutex // serializes reloads
+	cancel   func()
+}
+
+// NewReloadable binds the config and subscribes it to the watcher, nil means DefaultWatcher.
+// onReload, if not nil, is called after a new config with changed fields is published.
+func NewReloadable[T any](w *Watcher, onReload func(ReloadEvent)) (*Reloadable[T], error) {
+	cfg := new(T)
+	if err := Bind(cfg); err != nil {
+		return nil, err
+	}
+
+	if w == nil {
+		w = DefaultWatcher
+	}
+
+	r := &Reloadable[T]{onReload: onReload}
+	r.value.Store(cfg)
+	r.cancel = w.Subscribe(r.Reload)
+
+	return r, nil
+}
+
+// Load returns the current config, it must not be modified.
+func (r *Reloadable[T]) Load() *T {
+	return r.value.Load()
+}
+
+// Close stops reloading the config.
+func (r *Reloadable[T]) Close() {
+	r.cancel()
+}
+
+// Reload binds and validates a new config and publishes it if it has changed.
+func (r *Reloadable[T]) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old := r.value.Load()
+	cfg := new(T)
+	if err := bindStruct(cfg, old); err != nil {
+		return fmt.Errorf("config reload refused: %w", err)
+	}
+
+	oldFields := structFields(reflect.ValueOf(old).Elem(), "")
+	newFields := structFields(reflect.ValueOf(cfg).Elem(), "")
+
+	var event ReloadEvent
+	for i, f := range newFields {
+		o := oldFields[i]
+		if reflect.DeepEqual(o.value.Interface(), f.value.Interface()) {
+			continue
+		}
+		if f.tag.Get("reload") == "false" {
+			return fmt.Errorf("config reload refused: field %s (ENV %q) can't be changed without restart", f.path, f.key)
+		}
+		event.Changes = append(event.Changes, FieldChange{Field: f.path, Key: f.key, Old: o.String(), New: f.String()})
+	}
+
+	if len(event.Changes) == 0 {
+		return nil
+	}
+
+	r.value.Store(cfg)
+	if r.onReload != nil {
+		r.onReload(event)
+	}
+
+	return nil
+}
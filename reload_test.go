@@ -0,0 +1,79 @@
+package env_test
+
+import (
+	"testing"
+	"time"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reloadConfig struct {
+	Timeout  time.Duration      `env:"TEST_RELOAD_TIMEOUT" default:"5s"`
+	Password env.Secret[string] `env:"TEST_RELOAD_PASSWORD"`
+	Port     int                `env:"TEST_RELOAD_PORT" default:"8080" reload:"false"`
+	Limit    int                `env:"TEST_RELOAD_LIMIT" default:"10"`
+}
+
+func (c *reloadConfig) Validate() error {
+	if c.Limit <= 0 {
+		return assert.AnError
+	}
+	return nil
+}
+
+func TestReloadable(t *testing.T) {
+	t.Setenv("TEST_RELOAD_PASSWORD", "reload-pass-v1")
+
+	var reloadErr error
+	w := env.NewWatcher(env.WithErrorHandler(func(err error) { reloadErr = err }))
+
+	var events []env.ReloadEvent
+	cfg, err := env.NewReloadable[reloadConfig](w, func(e env.ReloadEvent) { events = append(events, e) })
+	require.NoError(t, err)
+	defer cfg.Close()
+
+	first := cfg.Load()
+	assert.Equal(t, 5*time.Second, first.Timeout)
+
+	// nothing changed
+	assert.NoError(t, w.Reload())
+	assert.Same(t, first, cfg.Load())
+	assert.Empty(t, events)
+
+	t.Setenv("TEST_RELOAD_TIMEOUT", "10s")
+	t.Setenv("TEST_RELOAD_PASSWORD", "reload-pass-v2")
+	assert.NoError(t, w.Reload())
+	assert.Equal(t, 10*time.Second, cfg.Load().Timeout)
+	assert.Equal(t, "reload-pass-v2", cfg.Load().Password.Reveal())
+	assert.Equal(t, 5*time.Second, first.Timeout)
+	require.Len(t, events, 1)
+	assert.Equal(t, []env.FieldChange{
+		{Field: "Timeout", Key: "TEST_RELOAD_TIMEOUT", Old: "5s", New: "10s"},
+		{Field: "Password", Key: "TEST_RELOAD_PASSWORD", Old: env.Redacted, New: env.Redacted},
+	}, events[0].Changes)
+	assert.NotContains(t, events[0].String(), "reload-pass")
+
+	// invalid value
+	t.Setenv("TEST_RELOAD_TIMEOUT", "soon")
+	assert.Error(t, w.Reload())
+	assert.Error(t, reloadErr)
+	assert.Equal(t, 10*time.Second, cfg.Load().Timeout)
+	t.Setenv("TEST_RELOAD_TIMEOUT", "10s")
+
+	// validation failure
+	t.Setenv("TEST_RELOAD_LIMIT", "0")
+	assert.ErrorIs(t, cfg.Reload(), assert.AnError)
+	assert.Equal(t, 10, cfg.Load().Limit)
+	t.Setenv("TEST_RELOAD_LIMIT", "10")
+
+	// non-reloadable field
+	t.Setenv("TEST_RELOAD_PORT", "9090")
+	t.Setenv("TEST_RELOAD_TIMEOUT", "20s")
+	err = cfg.Reload()
+	assert.ErrorContains(t, err, "field Port")
+	assert.Equal(t, 8080, cfg.Load().Port)
+	assert.Equal(t, 10*time.Second, cfg.Load().Timeout)
+	assert.Len(t, events, 1)
+}
@@ -0,0 +1,327 @@
+package env
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RemoteSource fetches variables from a remote service.
+type RemoteSource interface {
+	Fetch(ctx context.Context) (map[string]string, error)
+}
+
+// Remote turns a RemoteSource into a Source.
+// Values are cached for the configured TTL. The first lookup after it expires starts
+// a refresh in the background and lookups are served with the cached values meanwhile,
+// so they never wait for the network once the values are fetched.
+// If a refresh fails, the last known good values are used until the next attempt.
+//
+//	remote := env.NewRemote(env.NewHTTPProvider("http://config.internal/app"), env.WithTTL(time.Minute))
+//	if err := remote.Start(context.Background()); err != nil {
+//		...
+//	}
+//	env.SetSource(env.Chain(env.OS, remote)) // OS environment takes precedence
+type Remote struct {
+	src            RemoteSource
+	ttl            time.Duration
+	fetchTimeout   time.Duration
+	startupTimeout time.Duration
+
+	fetchMu sync.Mutex // serializes fetches, it's never held by lookups of fetched values
+
+	mu         sync.Mutex // guards the fields below
+	values     map[string]string
+	fetchedAt  time.Time
+	lastErr    error
+	refreshing bool
+}
+
+// RemoteOption configures a Remote.
+type RemoteOption func(*Remote)
+
+// WithTTL sets how long fetched values are cached, default is 1 minute.
+// Zero TTL means values are fetched once and never refreshed.
+func WithTTL(ttl time.Duration) RemoteOption {
+	return func(r *Remote) { r.ttl = ttl }
+}
+
+// WithFetchTimeout limits the duration of each refresh, default is 5 seconds.
+func WithFetchTimeout(timeout time.Duration) RemoteOption {
+	return func(r *Remote) { r.fetchTimeout = timeout }
+}
+
+// WithStartupTimeout limits the duration of the initial fetch made by Start, default is 10 seconds.
+func WithStartupTimeout(timeout time.Duration) RemoteOption {
+	return func(r *Remote) { r.startupTimeout = timeout }
+}
+
+// NewRemote creates a Source backed by the remote source.
+func NewRemote(src RemoteSource, opts ...RemoteOption) *Remote {
+	r := &Remote{
+		src:            src,
+		ttl:            time.Minute,
+		fetchTimeout:   5 * time.Second,
+		startupTimeout: 10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Start makes the initial fetch, limited by the startup timeout.
+// If it's not called, the values are fetched on the first lookup.
+func (r *Remote) Start(ctx context.Context) error {
+	r.fetchMu.Lock()
+	defer r.fetchMu.Unlock()
+	return r.fetch(ctx, r.startupTimeout)
+}
+
+// Refresh fetches the values ignoring the TTL.
+func (r *Remote) Refresh(ctx context.Context) error {
+	r.fetchMu.Lock()
+	defer r.fetchMu.Unlock()
+	return r.fetch(ctx, r.fetchTimeout)
+}
+
+// fetch fetches the values, fetchMu must be held.
+func (r *Remote) fetch(ctx context.Context, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	values, err := r.src.Fetch(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// the attempt time is recorded even on failure, so a broken remote
+	// is not hammered on every lookup
+	r.fetchedAt = time.Now()
+	r.lastErr = err
+	if err != nil {
+		return err
+	}
+
+	r.values = values
+	return nil
+}
+
+// fetchFirst makes the first fetch on lookup, concurrent lookups wait for a single fetch.
+func (r *Remote) fetchFirst() {
+	r.fetchMu.Lock()
+	defer r.fetchMu.Unlock()
+
+	r.mu.Lock()
+	fetched := !r.fetchedAt.IsZero()
+	r.mu.Unlock()
+	if !fetched {
+		_ = r.fetch(context.Background(), r.fetchTimeout)
+	}
+}
+
+// Reload implements Reloader, it's Refresh with the background context.
+func (r *Remote) Reload() error {
+	return r.Refresh(context.Background())
+}
+
+// Lookup implements Source.
+// It returns an error only if there are no last known good values.
+func (r *Remote) Lookup(key string) (string, bool, error) {
+	values, err := r.current()
+	if err != nil {
+		return "", false, err
+	}
+	value, ok := values[key]
+	return value, ok, nil
+}
+
+// Keys implements KeyLister.
+func (r *Remote) Keys() ([]string, error) {
+	values, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// current returns the last known good values, making the first fetch if needed
+// and starting a background refresh if the values are expired.
+func (r *Remote) current() (map[string]string, error) {
+	r.mu.Lock()
+	fetched := !r.fetchedAt.IsZero()
+	if fetched && r.ttl > 0 && time.Since(r.fetchedAt) > r.ttl && !r.refreshing {
+		r.refreshing = true
+		go func() {
+			_ = r.Refresh(context.Background())
+			r.mu.Lock()
+			r.refreshing = false
+			r.mu.Unlock()
+		}()
+	}
+	r.mu.Unlock()
+
+	if !fetched {
+		r.fetchFirst()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.values == nil && r.lastErr != nil {
+		return nil, fmt.Errorf("failed to fetch remote config: %w", r.lastErr)
+	}
+	return r.values, nil
+}
+
+// LastError returns the error of the last fetch attempt, if any.
+func (r *Remote) LastError() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastErr
+}
+
+// HTTPProvider is a RemoteSource which GETs a JSON object or a dotenv document.
+// The document format is detected by the Content-Type header, falling back to dotenv.
+// ETag and If-None-Match headers are used to avoid downloading unchanged documents.
+type HTTPProvider struct {
+	url    string
+	client *http.Client
+	header http.Header
+
+	mu     sync.Mutex
+	etag   string
+	values map[string]string
+}
+
+// NewHTTPProvider creates a provider fetching the document from the url with http.DefaultClient.
+func NewHTTPProvider(url string) *HTTPProvider {
+	return &HTTPProvider{url: url, client: http.DefaultClient, header: make(http.Header)}
+}
+
+// WithClient sets the HTTP client used for requests.
+func (p *HTTPProvider) WithClient(client *http.Client) *HTTPProvider {
+	p.client = client
+	return p
+}
+
+// WithHeader adds a header sent with every request, e.g. Authorization.
+func (p *HTTPProvider) WithHeader(key, value string) *HTTPProvider {
+	p.header.Add(key, value)
+	return p
+}
+
+// Fetch implements RemoteSource.
+func (p *HTTPProvider) Fetch(ctx context.Context) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range p.header {
+		req.Header[k] = v
+	}
+
+	p.mu.Lock()
+	etag, cached := p.etag, p.values
+	p.mu.Unlock()
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		return cached, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := parseDocument(resp.Header.Get("Content-Type"), body)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.etag, p.values = resp.Header.Get("ETag"), values
+	p.mu.Unlock()
+
+	return values, nil
+}
+
+// parseDocument parses a JSON object or a dotenv document into variables.
+func parseDocument(contentType string, body []byte) (map[string]string, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/json" || mediaType == "" && bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		return parseJSONDocument(body)
+	}
+
+	entries, err := ParseDotenv(body)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(entries))
+	for _, e := range entries {
+		values[e.Key] = e.Value
+	}
+	return values, nil
+}
+
+// parseJSONDocument parses a flat JSON object. Strings, numbers and booleans are
+// converted to strings, nulls are skipped, nested arrays and objects are kept as JSON.
+func parseJSONDocument(body []byte) (map[string]string, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("document must be a JSON object: %w", err)
+	}
+	if doc == nil {
+		return nil, errors.New("document must be a JSON object")
+	}
+
+	values := make(map[string]string, len(doc))
+	for k, raw := range doc {
+		raw = bytes.TrimSpace(raw)
+		switch {
+		case bytes.Equal(raw, []byte("null")):
+			continue
+		case len(raw) > 0 && raw[0] == '"':
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return nil, fmt.Errorf("invalid value of %q: %w", k, err)
+			}
+			values[k] = s
+		default:
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, raw); err != nil {
+				return nil, fmt.Errorf("invalid value of %q: %w", k, err)
+			}
+			values[k] = compact.String()
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,153 @@
+package env_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProvider(t *testing.T) {
+	var requests, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write([]byte(`{"HTTP_PORT": 8080, "DEBUG": true, "NAME": "demo", "EMPTY": null, "HOSTS": ["a", "b"]}`))
+	}))
+	defer srv.Close()
+
+	p := env.NewHTTPProvider(srv.URL).WithHeader("Authorization", "Bearer token")
+	expected := map[string]string{"HTTP_PORT": "8080", "DEBUG": "true", "NAME": "demo", "HOSTS": `["a","b"]`}
+
+	values, err := p.Fetch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, expected, values)
+
+	values, err = p.Fetch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, expected, values)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, int32(1), notModified.Load())
+}
+
+func TestHTTPProviderDotenv(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("# remote\nHTTP_PORT=8080\nNAME=\"demo app\"\n"))
+	}))
+	defer srv.Close()
+
+	values, err := env.NewHTTPProvider(srv.URL).Fetch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"HTTP_PORT": "8080", "NAME": "demo app"}, values)
+}
+
+func TestRemote(t *testing.T) {
+	var failing atomic.Bool
+	var port atomic.Int32
+	port.Store(8080)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"TEST_REMOTE_PORT": ` + strconv.Itoa(int(port.Load())) + `}`))
+	}))
+	defer srv.Close()
+
+	remote := env.NewRemote(env.NewHTTPProvider(srv.URL), env.WithTTL(time.Millisecond))
+	require.NoError(t, remote.Start(context.Background()))
+
+	env.SetSource(env.Chain(env.OS, remote))
+	defer env.SetSource(nil)
+
+	assert.Equal(t, 8080, env.MustInt[int]("TEST_REMOTE_PORT"))
+
+	// OS environment takes precedence
+	t.Setenv("TEST_REMOTE_PORT", "9090")
+	assert.Equal(t, 9090, env.MustInt[int]("TEST_REMOTE_PORT"))
+	env.SetSource(env.Chain(remote, env.OS))
+	assert.Equal(t, 8080, env.MustInt[int]("TEST_REMOTE_PORT"))
+
+	// values are refreshed in the background after TTL
+	port.Store(7070)
+	time.Sleep(5 * time.Millisecond)
+	assert.Eventually(t, func() bool {
+		return env.MustInt[int]("TEST_REMOTE_PORT") == 7070
+	}, time.Second, time.Millisecond)
+
+	// last known good values are used on failure
+	failing.Store(true)
+	time.Sleep(5 * time.Millisecond)
+	assert.Eventually(t, func() bool {
+		env.MustInt[int]("TEST_REMOTE_PORT")
+		return remote.LastError() != nil
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 7070, env.MustInt[int]("TEST_REMOTE_PORT"))
+}
+
+func TestRemoteSlowRefresh(t *testing.T) {
+	var slow atomic.Bool
+	var requests atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if slow.Load() {
+			<-release
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"TEST_REMOTE_SLOW_PORT": 8080}`))
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	remote := env.NewRemote(env.NewHTTPProvider(srv.URL), env.WithTTL(time.Millisecond))
+	require.NoError(t, remote.Start(context.Background()))
+
+	// lookups are served with the cached values while a single refresh is in flight
+	slow.Store(true)
+	time.Sleep(5 * time.Millisecond)
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		value, ok, err := remote.Lookup("TEST_REMOTE_SLOW_PORT")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "8080", value)
+	}
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Eventually(t, func() bool { return requests.Load() == 2 }, time.Second, time.Millisecond)
+}
+
+func TestRemoteStartupTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	remote := env.NewRemote(env.NewHTTPProvider(srv.URL), env.WithStartupTimeout(20*time.Millisecond))
+	start := time.Now()
+	assert.Error(t, remote.Start(context.Background()))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	// no last known good values
+	_, _, err := remote.Lookup("TEST_REMOTE_PORT")
+	assert.Error(t, err)
+}
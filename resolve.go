@@ -0,0 +1,252 @@
+package env
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReferencePrefix is the prefix of values holding a reference to the actual value,
+// e.g. "ref+file:///run/secrets/db", "ref+base64:LS0t..." or "ref+exec://op read op://vault/item".
+const ReferencePrefix = "ref+"
+
+// Resolver resolves a reference into the actual value.
+// The reference is passed without the ReferencePrefix, e.g. "file:///run/secrets/db".
+type Resolver interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// ResolverFunc is an adapter to allow the use of ordinary functions as a Resolver.
+type ResolverFunc func(ctx context.Context, ref string) (string, error)
+
+// Resolve implements Resolver.
+func (f ResolverFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+// ResolverRegistry maps reference schemes to resolvers and caches resolved values.
+type ResolverRegistry struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	resolvers map[string]Resolver
+	cache     map[string]resolvedValue
+}
+
+type resolvedValue struct {
+	value   string
+	expires time.Time
+}
+
+// NewResolverRegistry creates a registry with the "file" and "base64" resolvers registered.
+// The "exec" resolver must be registered explicitly with the list of permitted commands.
+// cacheTTL - how long resolved values are cached, 0 disables caching.
+func NewResolverRegistry(cacheTTL time.Duration) *ResolverRegistry {
+	r := &ResolverRegistry{
+		ttl:       cacheTTL,
+		resolvers: make(map[string]Resolver),
+		cache:     make(map[string]resolvedValue),
+	}
+	r.Register("file", FileResolver())
+	r.Register("base64", Base64Resolver())
+	return r
+}
+
+// Register maps the scheme to the resolver, replacing the previous one.
+func (r *ResolverRegistry) Register(scheme string, resolver Resolver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resolvers[strings.ToLower(scheme)] = resolver
+}
+
+// Resolve resolves the reference with the resolver registered for its scheme.
+// The reference may be passed with or without the ReferencePrefix.
+func (r *ResolverRegistry) Resolve(ctx context.Context, ref string) (string, error) {
+	ref = strings.TrimPrefix(ref, ReferencePrefix)
+
+	scheme, _, ok := strings.Cut(ref, ":")
+	if !ok || scheme == "" {
+		return "", errors.New("reference must start with a scheme")
+	}
+
+	r.mu.Lock()
+	resolver, ok := r.resolvers[strings.ToLower(scheme)]
+	cached, hit := r.cache[ref]
+	r.mu.Unlock()
+
+	if !ok {
+		return "", fmt.Errorf("unsupported reference scheme %q", scheme)
+	}
+	if hit && time.Now().Before(cached.expires) {
+		return cached.value, nil
+	}
+
+	value, err := resolver.Resolve(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	if r.ttl > 0 {
+		r.mu.Lock()
+		r.cache[ref] = resolvedValue{value: value, expires: time.Now().Add(r.ttl)}
+		r.mu.Unlock()
+	}
+
+	return value, nil
+}
+
+// ClearCache drops all cached values.
+func (r *ResolverRegistry) ClearCache() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = make(map[string]resolvedValue)
+}
+
+// IsReference reports whether the value has the reference prefix.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, ReferencePrefix)
+}
+
+// ResolvingSource wraps the source, so references ("ref+" prefixed values)
+// are transparently resolved with the registry before parsing.
+// Resolved values are registered as secrets, so every Redactor masks them.
+//
+//	reg := env.NewResolverRegistry(5 * time.Minute)
+//	reg.Register("exec", env.ExecResolver(10*time.Second, "op"))
+//	env.SetSource(env.ResolvingSource(env.OS, reg))
+func ResolvingSource(src Source, reg *ResolverRegistry) Source {
+	return wrap(src, func(key string) (string, bool, error) {
+		value, ok, err := src.Lookup(key)
+		if err != nil || !ok || !IsReference(value) {
+			return value, ok, err
+		}
+
+		res, err := reg.Resolve(context.Background(), value)
+		if err != nil {
+			return "", true, fmt.Errorf("failed to resolve reference %q of ENV %q: %w", value, key, err)
+		}
+		secrets.add(res)
+
+		return res, true, nil
+	})
+}
+
+// FileResolver resolves "file://" references by reading the file,
+// e.g. "file:///run/secrets/db". A single trailing newline is trimmed.
+func FileResolver() Resolver {
+	return ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		u, err := url.Parse(ref)
+		if err != nil || u.Path == "" {
+			return "", errors.New("file reference must be in the file:///path format")
+		}
+
+		path := u.Path
+		if u.Host != "" {
+			// relative path, e.g. "file://secrets/db"
+			path = filepath.Join(u.Host, u.Path)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+
+		return trimNewline(string(data)), nil
+	})
+}
+
+// Base64Resolver resolves "base64:" references by decoding the rest of the reference,
+// e.g. "base64:c2VjcmV0". Both standard and URL encodings, padded or not, are accepted.
+func Base64Resolver() Resolver {
+	return ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		encoded := strings.TrimPrefix(strings.TrimPrefix(ref, "base64:"), "//")
+		for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+			if data, err := enc.DecodeString(encoded); err == nil {
+				return string(data), nil
+			}
+		}
+		return "", errors.New("base64 reference must be base64 encoded")
+	})
+}
+
+// ExecResolver resolves "exec://" references by running the command and reading its output,
+// e.g. "exec://op read op://vault/item". The command is split by whitespace and run
+// without a shell. Only commands from the allowlist are permitted, they must match
+// exactly as written, e.g. "op" or "/usr/local/bin/op". The command is killed after timeout,
+// and its output is not waited for longer than a short delay after it exits or is killed,
+// even if processes started by the command keep it open. A single trailing newline
+// of the output is trimmed, the stderr of a failed command is included in the error.
+func ExecResolver(timeout time.Duration, allowlist ...string) Resolver {
+	return ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		args := strings.Fields(strings.TrimPrefix(strings.TrimPrefix(ref, "exec:"), "//"))
+		if len(args) == 0 {
+			return "", errors.New("exec reference must contain a command")
+		}
+
+		permitted := false
+		for _, cmd := range allowlist {
+			if cmd == args[0] {
+				permitted = true
+				break
+			}
+		}
+		if !permitted {
+			return "", fmt.Errorf("command %q is not permitted", args[0])
+		}
+
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		// don't wait for processes left by the command which keep its output open
+		cmd.WaitDelay = execWaitDelay
+		if err := cmd.Run(); err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			if msg := commandStderr(stderr.String()); msg != "" {
+				return "", fmt.Errorf("command %q: %w: %s", args[0], err, msg)
+			}
+			return "", fmt.Errorf("command %q: %w", args[0], err)
+		}
+
+		return trimNewline(stdout.String()), nil
+	})
+}
+
+// execWaitDelay is how long ExecResolver waits for the output to be closed
+// after the command exits or is killed.
+const execWaitDelay = 100 * time.Millisecond
+
+// maxStderrLen limits the length of the command stderr included in errors.
+const maxStderrLen = 512
+
+// commandStderr returns the trimmed stderr of a command for an error message.
+func commandStderr(stderr string) string {
+	stderr = strings.TrimSpace(stderr)
+	if len(stderr) > maxStderrLen {
+		stderr = stderr[:maxStderrLen] + "..."
+	}
+	return stderr
+}
+
+// trimNewline trims a single trailing newline.
+func trimNewline(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r")
+}
@@ -0,0 +1,124 @@
+package env_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db")
+	require.NoError(t, os.WriteFile(path, []byte("file-pa55\n"), 0o600))
+
+	reg := env.NewResolverRegistry(0)
+	ctx := context.Background()
+
+	v, err := reg.Resolve(ctx, "ref+file://"+path)
+	assert.NoError(t, err)
+	assert.Equal(t, "file-pa55", v)
+
+	v, err = reg.Resolve(ctx, "base64:LS0tLS1CRUdJTg==")
+	assert.NoError(t, err)
+	assert.Equal(t, "-----BEGIN", v)
+	v, err = reg.Resolve(ctx, "ref+base64:LS0tLS1CRUdJTg")
+	assert.NoError(t, err)
+	assert.Equal(t, "-----BEGIN", v)
+
+	_, err = reg.Resolve(ctx, "ref+vault://secret/db")
+	assert.EqualError(t, err, `unsupported reference scheme "vault"`)
+	_, err = reg.Resolve(ctx, "ref+file://"+path+".missing")
+	assert.Error(t, err)
+	_, err = reg.Resolve(ctx, "ref+base64:!!!")
+	assert.Error(t, err)
+	_, err = reg.Resolve(ctx, "ref+exec://echo hello")
+	assert.Error(t, err)
+}
+
+func TestResolverRegistryCache(t *testing.T) {
+	var calls int32
+	reg := env.NewResolverRegistry(time.Hour)
+	reg.Register("counter", env.ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return ref, nil
+	}))
+
+	for i := 0; i < 3; i++ {
+		v, err := reg.Resolve(context.Background(), "ref+counter:a")
+		assert.NoError(t, err)
+		assert.Equal(t, "counter:a", v)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	reg.ClearCache()
+	_, err := reg.Resolve(context.Background(), "ref+counter:a")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestExecResolver(t *testing.T) {
+	reg := env.NewResolverRegistry(0)
+	reg.Register("exec", env.ExecResolver(time.Second, "echo", "sleep"))
+	ctx := context.Background()
+
+	v, err := reg.Resolve(ctx, "ref+exec://echo exec-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "exec-token", v)
+
+	_, err = reg.Resolve(ctx, "ref+exec://cat /etc/passwd")
+	assert.EqualError(t, err, `command "cat" is not permitted`)
+	_, err = reg.Resolve(ctx, "ref+exec:///bin/echo hi")
+	assert.EqualError(t, err, `command "/bin/echo" is not permitted`)
+
+	reg.Register("exec", env.ExecResolver(50*time.Millisecond, "sleep"))
+	_, err = reg.Resolve(ctx, "ref+exec://sleep 5")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), err)
+}
+
+func TestExecResolverShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	reg := env.NewResolverRegistry(0)
+	reg.Register("exec", env.ExecResolver(time.Second, "sh"))
+	ctx := context.Background()
+
+	_, err := reg.Resolve(ctx, "ref+exec://sh -c echo${IFS}vault${IFS}is${IFS}locked>&2;exit${IFS}1")
+	assert.EqualError(t, err, `command "sh": exit status 1: vault is locked`)
+
+	// a background process keeping the output open doesn't block the resolver
+	start := time.Now()
+	_, err = reg.Resolve(ctx, "ref+exec://sh -c sleep${IFS}5&")
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestResolvingSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db")
+	require.NoError(t, os.WriteFile(path, []byte("resolved-pa55\n"), 0o600))
+
+	t.Setenv("TEST_REF_DB_PASS", "ref+file://"+path)
+	t.Setenv("TEST_REF_PORT", "ref+base64:NTQzMg==")
+	t.Setenv("TEST_REF_PLAIN", "plain")
+	t.Setenv("TEST_REF_BROKEN", "ref+file:///nonexistent/secret")
+
+	env.SetSource(env.ResolvingSource(env.OS, env.NewResolverRegistry(time.Minute)))
+	defer env.SetSource(nil)
+
+	assert.Equal(t, "resolved-pa55", env.MustString("TEST_REF_DB_PASS"))
+	assert.Equal(t, 5432, env.MustInt[int]("TEST_REF_PORT"))
+	assert.Equal(t, "plain", env.GetString("TEST_REF_PLAIN", ""))
+	assert.Equal(t, "fallback", env.GetString("TEST_REF_BROKEN", "fallback"))
+
+	_, err := env.LookupSecret[string]("TEST_REF_BROKEN")
+	assert.ErrorContains(t, err, `"TEST_REF_BROKEN"`)
+	assert.ErrorContains(t, err, `"ref+file:///nonexistent/secret"`)
+}
@@ -0,0 +1,173 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrExcluded is returned when a variable is set together with a variable it excludes.
+var ErrExcluded = errors.New("must not be set")
+
+// Rule is a requirement involving several variables, see CheckRules.
+// lookup returns the value of the variable and whether it's set and not empty.
+type Rule func(lookup func(key string) (string, bool)) error
+
+// RequiredIf requires the variable when the condition variable has the value,
+// e.g. RequiredIf("SMTP_PASSWORD", "MAIL_DRIVER", "smtp").
+func RequiredIf(key, condKey, condValue string) Rule {
+	return func(lookup func(string) (string, bool)) error {
+		if v, ok := lookup(condKey); ok && v == condValue {
+			if _, ok := lookup(key); !ok {
+				return fmt.Errorf("ENV %q is required when %s=%s: %w", key, condKey, condValue, ErrNotSet)
+			}
+		}
+		return nil
+	}
+}
+
+// RequiredWith requires the variable when any of the others is set,
+// e.g. RequiredWith("TLS_KEY", "TLS_CERT").
+func RequiredWith(key string, others ...string) Rule {
+	return func(lookup func(string) (string, bool)) error {
+		if _, ok := lookup(key); ok {
+			return nil
+		}
+		for _, other := range others {
+			if _, ok := lookup(other); ok {
+				return fmt.Errorf("ENV %q is required when %q is set: %w", key, other, ErrNotSet)
+			}
+		}
+		return nil
+	}
+}
+
+// RequiredWithout requires the variable when any of the others is not set,
+// e.g. RequiredWithout("DATABASE_URL", "DB_HOST").
+func RequiredWithout(key string, others ...string) Rule {
+	return func(lookup func(string) (string, bool)) error {
+		if _, ok := lookup(key); ok {
+			return nil
+		}
+		for _, other := range others {
+			if _, ok := lookup(other); !ok {
+				return fmt.Errorf("ENV %q is required when %q is not set: %w", key, other, ErrNotSet)
+			}
+		}
+		return nil
+	}
+}
+
+// ExcludedWith forbids the variable when any of the others is set,
+// e.g. ExcludedWith("TLS_INSECURE", "TLS_CERT").
+func ExcludedWith(key string, others ...string) Rule {
+	return func(lookup func(string) (string, bool)) error {
+		if _, ok := lookup(key); !ok {
+			return nil
+		}
+		for _, other := range others {
+			if _, ok := lookup(other); ok {
+				return fmt.Errorf("ENV %q %w when %q is set", key, ErrExcluded, other)
+			}
+		}
+		return nil
+	}
+}
+
+// MutuallyExclusive allows at most one of the variables to be set,
+// e.g. MutuallyExclusive("AUTH_TOKEN", "AUTH_PASSWORD").
+func MutuallyExclusive(keys ...string) Rule {
+	return func(lookup func(string) (string, bool)) error {
+		var set []string
+		for _, key := range keys {
+			if _, ok := lookup(key); ok {
+				set = append(set, fmt.Sprintf("%q", key))
+			}
+		}
+		if len(set) > 1 {
+			return fmt.Errorf("ENV %s are mutually exclusive: %w", strings.Join(set, ", "), ErrExcluded)
+		}
+		return nil
+	}
+}
+
+// CheckRules checks the rules against the current source, all violations are returned joined.
+//
+//	err := env.CheckRules(
+//		env.RequiredIf("SMTP_PASSWORD", "MAIL_DRIVER", "smtp"),
+//		env.RequiredWith("TLS_KEY", "TLS_CERT"),
+//	)
+func CheckRules(rules ...Rule) error {
+	return checkRules(rules, peekEnv)
+}
+
+// peekEnv returns the value of the variable or its alias and whether it's set and not empty.
+// Unlike lookupEnv it doesn't scrub the variable, scrubbed variables are reported as set.
+func peekEnv(key string) (string, bool) {
+	registry.add(key)
+	value, used, err := lookupAliased(CurrentSource(), key, aliasesOf(key))
+	if err == nil && used == "" && consumer.has(key) {
+		return "", true
+	}
+	return value, err == nil && used != "" && value != ""
+}
+
+func checkRules(rules []Rule, lookup func(string) (string, bool)) error {
+	var errs []error
+	for _, rule := range rules {
+		if err := rule(lookup); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// tagRules returns the rules declared with the field tags:
+// required_if:"KEY=value,KEY2=value2" (any condition), required_with:"KEY,KEY2",
+// required_without:"KEY,KEY2", excluded_with:"KEY,KEY2" and exclusive:"group".
+func tagRules(fields []boundField) ([]Rule, error) {
+	var rules []Rule
+	var groups []string
+	members := make(map[string][]string)
+
+	for _, f := range fields {
+		for _, cond := range splitTag(f.tag.Get("required_if")) {
+			condKey, condValue, ok := strings.Cut(cond, "=")
+			if !ok {
+				return nil, fmt.Errorf("field %s: required_if condition %q must be in the KEY=value format", f.path, cond)
+			}
+			rules = append(rules, RequiredIf(f.key, condKey, condValue))
+		}
+		if others := splitTag(f.tag.Get("required_with")); len(others) > 0 {
+			rules = append(rules, RequiredWith(f.key, others...))
+		}
+		if others := splitTag(f.tag.Get("required_without")); len(others) > 0 {
+			rules = append(rules, RequiredWithout(f.key, others...))
+		}
+		if others := splitTag(f.tag.Get("excluded_with")); len(others) > 0 {
+			rules = append(rules, ExcludedWith(f.key, others...))
+		}
+		if group := f.tag.Get("exclusive"); group != "" {
+			if _, ok := members[group]; !ok {
+				groups = append(groups, group)
+			}
+			members[group] = append(members[group], f.key)
+		}
+	}
+
+	for _, group := range groups {
+		rules = append(rules, MutuallyExclusive(members[group]...))
+	}
+	return rules, nil
+}
+
+// splitTag splits a comma-separated tag value, empty items are skipped.
+func splitTag(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
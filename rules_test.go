@@ -0,0 +1,79 @@
+package env_test
+
+import (
+	"errors"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRules(t *testing.T) {
+	rules := []env.Rule{
+		env.RequiredIf("TEST_RULES_SMTP_PASSWORD", "TEST_RULES_MAIL_DRIVER", "smtp"),
+		env.RequiredWith("TEST_RULES_TLS_KEY", "TEST_RULES_TLS_CERT"),
+		env.RequiredWithout("TEST_RULES_DATABASE_URL", "TEST_RULES_DB_HOST"),
+		env.ExcludedWith("TEST_RULES_TLS_INSECURE", "TEST_RULES_TLS_CERT"),
+		env.MutuallyExclusive("TEST_RULES_AUTH_TOKEN", "TEST_RULES_AUTH_PASSWORD"),
+	}
+
+	t.Setenv("TEST_RULES_MAIL_DRIVER", "log")
+	t.Setenv("TEST_RULES_DB_HOST", "localhost")
+	require.NoError(t, env.CheckRules(rules...))
+
+	t.Setenv("TEST_RULES_MAIL_DRIVER", "smtp")
+	t.Setenv("TEST_RULES_TLS_CERT", "cert.pem")
+	t.Setenv("TEST_RULES_TLS_INSECURE", "true")
+	t.Setenv("TEST_RULES_DB_HOST", "")
+	t.Setenv("TEST_RULES_AUTH_TOKEN", "token")
+	t.Setenv("TEST_RULES_AUTH_PASSWORD", "password")
+
+	err := env.CheckRules(rules...)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, env.ErrNotSet))
+	assert.True(t, errors.Is(err, env.ErrExcluded))
+	assert.Equal(t, `ENV "TEST_RULES_SMTP_PASSWORD" is required when TEST_RULES_MAIL_DRIVER=smtp: not set
+ENV "TEST_RULES_TLS_KEY" is required when "TEST_RULES_TLS_CERT" is set: not set
+ENV "TEST_RULES_DATABASE_URL" is required when "TEST_RULES_DB_HOST" is not set: not set
+ENV "TEST_RULES_TLS_INSECURE" must not be set when "TEST_RULES_TLS_CERT" is set
+ENV "TEST_RULES_AUTH_TOKEN", "TEST_RULES_AUTH_PASSWORD" are mutually exclusive: must not be set`, err.Error())
+}
+
+type rulesConfig struct {
+	Driver   string `env:"TEST_RULES_BIND_DRIVER" default:"smtp"`
+	Password string `env:"TEST_RULES_BIND_PASSWORD" required_if:"TEST_RULES_BIND_DRIVER=smtp,TEST_RULES_BIND_DRIVER=ses"`
+	Cert     string `env:"TEST_RULES_BIND_CERT"`
+	Key      string `env:"TEST_RULES_BIND_KEY" required_with:"TEST_RULES_BIND_CERT"`
+	Token    string `env:"TEST_RULES_BIND_TOKEN" exclusive:"auth"`
+	Secret   string `env:"TEST_RULES_BIND_SECRET" exclusive:"auth"`
+	Port     int    `env:"TEST_RULES_BIND_PORT"`
+}
+
+func TestBindRules(t *testing.T) {
+	t.Setenv("TEST_RULES_BIND_CERT", "cert.pem")
+	t.Setenv("TEST_RULES_BIND_TOKEN", "token")
+	t.Setenv("TEST_RULES_BIND_SECRET", "secret")
+	t.Setenv("TEST_RULES_BIND_PORT", "http")
+
+	// binding errors and all violations are reported together
+	var cfg rulesConfig
+	err := env.Bind(&cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `field Port: ENV "TEST_RULES_BIND_PORT"`)
+	assert.Contains(t, err.Error(), `ENV "TEST_RULES_BIND_PASSWORD" is required when TEST_RULES_BIND_DRIVER=smtp: not set`)
+	assert.Contains(t, err.Error(), `ENV "TEST_RULES_BIND_KEY" is required when "TEST_RULES_BIND_CERT" is set: not set`)
+	assert.Contains(t, err.Error(), `ENV "TEST_RULES_BIND_TOKEN", "TEST_RULES_BIND_SECRET" are mutually exclusive`)
+
+	t.Setenv("TEST_RULES_BIND_DRIVER", "log")
+	t.Setenv("TEST_RULES_BIND_KEY", "key.pem")
+	t.Setenv("TEST_RULES_BIND_SECRET", "")
+	t.Setenv("TEST_RULES_BIND_PORT", "8080")
+	require.NoError(t, env.Bind(&cfg))
+	assert.Equal(t, "key.pem", cfg.Key)
+
+	type invalid struct {
+		Password string `env:"TEST_RULES_BIND_PASSWORD" required_if:"TEST_RULES_BIND_DRIVER"`
+	}
+	assert.Error(t, env.Bind(&invalid{}))
+}
@@ -0,0 +1,129 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrConsumed is returned when a variable is read after it was scrubbed from the process environment.
+var ErrConsumed = errors.New("already consumed")
+
+type consumption struct {
+	mu       sync.Mutex
+	patterns []string
+	consumed map[string]bool
+}
+
+var consumer = &consumption{consumed: make(map[string]bool)}
+
+// Scrub unsets the variables whose keys match the patterns (exact keys or globs, see path.Match)
+// from the process environment, so they are not visible in /proc/self/environ and not inherited
+// by child processes. Their values are registered as secrets, so every Redactor still masks them.
+// Scrubbed keys are recorded: later reads which no source can answer fail with ErrConsumed,
+// Get* functions log a warning and return the fallback value. Values provided by other sources
+// or set again in the process environment are read as usual. It returns the sorted scrubbed keys.
+func Scrub(patterns ...string) ([]string, error) {
+	var keys []string
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if !matchKey(patterns, key) {
+			continue
+		}
+		if err := consumer.consume(key, value); err != nil {
+			return keys, err
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// ConsumeOnRead enables scrubbing of the variables matching the patterns right after they are read
+// by any Get*, Must*, Lookup* function or Bind, see Scrub. DefaultSecretKeyPatterns are used
+// if no patterns are passed. Only values read as is from the process environment are scrubbed,
+// values provided by other sources (e.g. dotenv layers or decrypted values) are not affected.
+// A Reloadable config keeps the previous values of the fields whose variables were scrubbed.
+// It returns a function which disables the mode.
+//
+//	env.ConsumeOnRead("DB_PASSWORD", "*_TOKEN")
+//	password := env.MustString("DB_PASSWORD") // unset from the process environment
+//	env.MustString("DB_PASSWORD")             // panics: already consumed
+func ConsumeOnRead(patterns ...string) (disable func()) {
+	if len(patterns) == 0 {
+		patterns = DefaultSecretKeyPatterns
+	}
+
+	consumer.mu.Lock()
+	defer consumer.mu.Unlock()
+	consumer.patterns = patterns
+
+	return func() {
+		consumer.mu.Lock()
+		defer consumer.mu.Unlock()
+		consumer.patterns = nil
+	}
+}
+
+// Consumed returns the sorted keys scrubbed from the process environment.
+func Consumed() []string {
+	consumer.mu.Lock()
+	defer consumer.mu.Unlock()
+
+	keys := make([]string, 0, len(consumer.consumed))
+	for key := range consumer.consumed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// consume unsets the variable and records it.
+func (c *consumption) consume(key, value string) error {
+	if err := os.Unsetenv(key); err != nil {
+		return fmt.Errorf("failed to scrub ENV %q: %w", key, err)
+	}
+	secrets.add(value)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.consumed[key] = true
+	return nil
+}
+
+// has reports whether the variable was scrubbed.
+func (c *consumption) has(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.consumed[key]
+}
+
+// check returns ErrConsumed if any of the variables was scrubbed.
+func (c *consumption) check(keys ...string) error {
+	for _, key := range keys {
+		if c.has(key) {
+			logger().Warn("env: variable was already consumed", "key", key)
+			return fmt.Errorf("ENV %q was scrubbed after reading: %w", key, ErrConsumed)
+		}
+	}
+	return nil
+}
+
+// afterRead scrubs the variable if it matches the ConsumeOnRead patterns
+// and the value was read from the process environment.
+func (c *consumption) afterRead(key, value string) error {
+	c.mu.Lock()
+	match := matchKey(c.patterns, key)
+	c.mu.Unlock()
+	if !match {
+		return nil
+	}
+
+	if v, ok := os.LookupEnv(key); ok && v == value {
+		return c.consume(key, value)
+	}
+	return nil
+}
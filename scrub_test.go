@@ -0,0 +1,105 @@
+package env_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrub(t *testing.T) {
+	t.Setenv("TEST_SCRUB_DB_PASSWORD", "scrub-pass")
+	t.Setenv("TEST_SCRUB_API_KEY", "scrub-key")
+	t.Setenv("TEST_SCRUB_HOST", "db")
+
+	keys, err := env.Scrub("TEST_SCRUB_*_PASSWORD", "TEST_SCRUB_API_KEY")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TEST_SCRUB_API_KEY", "TEST_SCRUB_DB_PASSWORD"}, keys)
+	assert.Subset(t, env.Consumed(), keys)
+
+	_, exists := os.LookupEnv("TEST_SCRUB_DB_PASSWORD")
+	assert.False(t, exists)
+	assert.Equal(t, "db", env.MustString("TEST_SCRUB_HOST"))
+
+	assert.PanicsWithError(t, `failed to read required ENV "TEST_SCRUB_DB_PASSWORD": ENV "TEST_SCRUB_DB_PASSWORD" was scrubbed after reading: already consumed`, func() {
+		env.MustString("TEST_SCRUB_DB_PASSWORD")
+	})
+	_, err = env.LookupSecret[string]("TEST_SCRUB_API_KEY")
+	assert.True(t, errors.Is(err, env.ErrConsumed))
+	assert.Equal(t, "fallback", env.GetString("TEST_SCRUB_API_KEY", "fallback"))
+
+	assert.Equal(t, "password="+env.Redacted, env.NewRedactor().Redact("password=scrub-pass"))
+}
+
+func TestConsumeOnRead(t *testing.T) {
+	t.Setenv("TEST_CONSUME_API_TOKEN", "consume-token")
+	t.Setenv("TEST_CONSUME_HOST", "db")
+
+	disable := env.ConsumeOnRead()
+	defer disable()
+
+	assert.Equal(t, "db", env.MustString("TEST_CONSUME_HOST"))
+	assert.Equal(t, "db", env.MustString("TEST_CONSUME_HOST"))
+
+	assert.Equal(t, "consume-token", env.MustString("TEST_CONSUME_API_TOKEN"))
+	_, exists := os.LookupEnv("TEST_CONSUME_API_TOKEN")
+	assert.False(t, exists)
+	assert.Contains(t, env.Consumed(), "TEST_CONSUME_API_TOKEN")
+
+	_, err := env.LookupSecret[string]("TEST_CONSUME_API_TOKEN")
+	assert.True(t, errors.Is(err, env.ErrConsumed))
+
+	// values from other sources are not affected
+	env.SetSource(env.MapSource{"TEST_CONSUME_OTHER_TOKEN": "map-token"})
+	defer env.SetSource(nil)
+	assert.Equal(t, "map-token", env.MustString("TEST_CONSUME_OTHER_TOKEN"))
+	assert.Equal(t, "map-token", env.MustString("TEST_CONSUME_OTHER_TOKEN"))
+}
+
+type consumeConfig struct {
+	Token env.Secret[string] `env:"TEST_CONSUME_RELOAD_TOKEN" required:"true"`
+	Pass  string             `env:"TEST_CONSUME_RELOAD_PASSWORD"`
+	Limit int                `env:"TEST_CONSUME_RELOAD_LIMIT" default:"10"`
+}
+
+func TestConsumeOnReadReload(t *testing.T) {
+	t.Setenv("TEST_CONSUME_RELOAD_TOKEN", "reload-token-v1")
+	t.Setenv("TEST_CONSUME_RELOAD_PASSWORD", "os-pass")
+	layer := env.MapSource{"TEST_CONSUME_RELOAD_PASSWORD": "layer-pass-v1"}
+	env.SetSource(env.NewEnv(env.OSLayer(), env.NewLayer("layer", layer)))
+	defer env.SetSource(nil)
+
+	disable := env.ConsumeOnRead()
+	defer disable()
+
+	w := env.NewWatcher()
+	cfg, err := env.NewReloadable[consumeConfig](w, nil)
+	require.NoError(t, err)
+	defer cfg.Close()
+	assert.Equal(t, "reload-token-v1", cfg.Load().Token.Reveal())
+	assert.Equal(t, "layer-pass-v1", cfg.Load().Pass)
+
+	// only the value read from the process environment is scrubbed
+	_, exists := os.LookupEnv("TEST_CONSUME_RELOAD_TOKEN")
+	assert.False(t, exists)
+	assert.Equal(t, "os-pass", os.Getenv("TEST_CONSUME_RELOAD_PASSWORD"))
+	assert.NotContains(t, env.Consumed(), "TEST_CONSUME_RELOAD_PASSWORD")
+
+	// scrubbed fields keep their values, others are reloaded
+	layer["TEST_CONSUME_RELOAD_PASSWORD"] = "layer-pass-v2"
+	t.Setenv("TEST_CONSUME_RELOAD_LIMIT", "20")
+	require.NoError(t, w.Reload())
+	assert.Equal(t, "reload-token-v1", cfg.Load().Token.Reveal())
+	assert.Equal(t, "layer-pass-v2", cfg.Load().Pass)
+	assert.Equal(t, 20, cfg.Load().Limit)
+
+	// a value set again is read and scrubbed again
+	t.Setenv("TEST_CONSUME_RELOAD_TOKEN", "reload-token-v2")
+	require.NoError(t, w.Reload())
+	assert.Equal(t, "reload-token-v2", cfg.Load().Token.Reveal())
+	_, exists = os.LookupEnv("TEST_CONSUME_RELOAD_TOKEN")
+	assert.False(t, exists)
+}
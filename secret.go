@@ -0,0 +1,164 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// Redacted is the placeholder printed instead of secret values.
+const Redacted = "[REDACTED]"
+
+// Secret wraps a sensitive value so it's never printed, logged or marshaled by accident.
+// The plaintext value is only reachable through Reveal.
+// Secret can be used as a field type with anything that supports encoding.TextUnmarshaler.
+// String and []byte values read into a Secret are masked by every Redactor.
+type Secret[T any] struct {
+	value T
+}
+
+// NewSecret wraps the value into a Secret.
+// String and []byte values are registered, so every Redactor masks them.
+func NewSecret[T any](value T) Secret[T] {
+	registerSecret(value)
+	return Secret[T]{value: value}
+}
+
+// registerSecret registers string and []byte values, so every Redactor masks them.
+// Other types, e.g. numbers or booleans, are not registered: their text is usually short
+// and common, so masking it would corrupt unrelated output.
+func registerSecret(value any) {
+	switch v := value.(type) {
+	case string:
+		secrets.add(v)
+	case []byte:
+		secrets.add(string(v))
+	}
+}
+
+// Reveal returns the plaintext value.
+func (s Secret[T]) Reveal() T {
+	return s.value
+}
+
+// String implements fmt.Stringer.
+func (s Secret[T]) String() string {
+	return Redacted
+}
+
+// GoString implements fmt.GoStringer.
+func (s Secret[T]) GoString() string {
+	return Redacted
+}
+
+// Format implements fmt.Formatter, so every verb prints the placeholder.
+func (s Secret[T]) Format(f fmt.State, verb rune) {
+	_, _ = f.Write([]byte(Redacted))
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(Redacted)), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Secret[T]) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// reveal returns the plaintext value of any Secret type, see secretValue.
+func (s Secret[T]) reveal() any {
+	return s.value
+}
+
+// secretValue is implemented by every Secret type.
+type secretValue interface {
+	reveal() any
+}
+
+// LogValue implements slog.LogValuer.
+func (s Secret[T]) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// The returned error never contains the value.
+func (s *Secret[T]) UnmarshalText(text []byte) error {
+	res, err := parseValue[T](string(text))
+	if err != nil {
+		return secretError(err)
+	}
+	registerSecret(res)
+	s.value = res
+	return nil
+}
+
+// secretError returns a parsing error that doesn't echo the secret value.
+func secretError(err error) error {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return numErr.Err
+	}
+	if errors.Is(err, strconv.ErrSyntax) {
+		return strconv.ErrSyntax
+	}
+	return errors.New("invalid value")
+}
+
+// GetSecret func returns environment variable value as a secret value,
+// If variable doesn't exist, is not set or unparsable, returns fallback value
+func GetSecret[T any](key string, fallback T) Secret[T] {
+	// the fallback is not a secret read from the environment, so it's not registered
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
+		return Secret[T]{value: fallback}
+	}
+
+	res, err := parseValue[T](value)
+	if err != nil {
+		return Secret[T]{value: fallback}
+	}
+
+	return NewSecret(res)
+}
+
+// MustSecret func returns environment variable value as a secret value,
+// If variable doesn't exist, is not set or unparsable, then panics.
+// The panic message never contains the value.
+func MustSecret[T any](key string) Secret[T] {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
+	if !exists || value == "" {
+		panic(fmt.Errorf("required ENV %q is not set", key))
+	}
+
+	res, err := parseValue[T](value)
+	if err != nil {
+		panic(fmt.Errorf("required ENV %q must be a valid %T: %v", key, res, secretError(err)))
+	}
+
+	return NewSecret(res)
+}
+
+// LookupSecret func returns environment variable value as a secret value.
+// If variable doesn't exist or is not set, returns an error wrapping ErrNotSet.
+// If variable is unparsable, returns a parsing error which never contains the value.
+func LookupSecret[T any](key string) (Secret[T], error) {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		return Secret[T]{}, fmt.Errorf("failed to read ENV %q: %w", key, err)
+	}
+	if !exists || value == "" {
+		return Secret[T]{}, fmt.Errorf("ENV %q is %w", key, ErrNotSet)
+	}
+
+	res, err := parseValue[T](value)
+	if err != nil {
+		return Secret[T]{}, fmt.Errorf("ENV %q must be a valid %T: %w", key, res, secretError(err))
+	}
+
+	return NewSecret(res), nil
+}
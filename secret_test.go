@@ -0,0 +1,104 @@
+package env_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretRedacts(t *testing.T) {
+	s := env.NewSecret("sk_live_123")
+	assert.Equal(t, "sk_live_123", s.Reveal())
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%d"} {
+		assert.Equal(t, env.Redacted, fmt.Sprintf(format, s), format)
+	}
+	assert.NotContains(t, fmt.Sprintf("%+v", struct{ Key env.Secret[string] }{s}), "sk_live_123")
+	assert.NotContains(t, fmt.Sprint(errors.New(fmt.Sprint(s))), "sk_live_123")
+
+	data, err := json.Marshal(map[string]any{"key": s})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"key":"[REDACTED]"}`, string(data))
+
+	text, err := s.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, env.Redacted, string(text))
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("loaded", "key", s)
+	assert.Contains(t, buf.String(), "key=[REDACTED]")
+	assert.NotContains(t, buf.String(), "sk_live_123")
+}
+
+func TestSecretUnmarshalText(t *testing.T) {
+	var cfg struct {
+		Key  env.Secret[string] `json:"key"`
+		Port env.Secret[int]    `json:"port"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"key":"sk_live_123","port":"5432"}`), &cfg))
+	assert.Equal(t, "sk_live_123", cfg.Key.Reveal())
+	assert.Equal(t, 5432, cfg.Port.Reveal())
+
+	err := json.Unmarshal([]byte(`{"port":"secret-port"}`), &cfg)
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-port")
+}
+
+func TestGetSecret(t *testing.T) {
+	assert.Equal(t, "default", env.GetSecret("TEST_SECRET", "default").Reveal())
+	t.Setenv("TEST_SECRET", "s3cr3t")
+	assert.Equal(t, "s3cr3t", env.GetSecret("TEST_SECRET", "default").Reveal())
+	assert.Equal(t, []byte("s3cr3t"), env.GetSecret("TEST_SECRET", []byte(nil)).Reveal())
+	assert.Equal(t, 42, env.GetSecret("TEST_SECRET", 42).Reveal())
+
+	// fallback values are not registered as secrets
+	assert.Equal(t, "gs-fallback-host", env.GetSecret("TEST_SECRET_MISSING", "gs-fallback-host").Reveal())
+	assert.Equal(t, "host=gs-fallback-host", env.NewRedactor().Redact("host=gs-fallback-host"))
+}
+
+func TestMustSecret(t *testing.T) {
+	assert.Panics(t, func() { env.MustSecret[string]("TEST_ENV_2") })
+	t.Setenv("TEST_ENV", "secret-value")
+	assert.PanicsWithError(t, `required ENV "TEST_ENV" must be a valid int: invalid syntax`, func() { env.MustSecret[int]("TEST_ENV") })
+	assert.PanicsWithError(t, `required ENV "TEST_ENV" must be a valid time.Duration: invalid value`, func() { env.MustSecret[time.Duration]("TEST_ENV") })
+
+	assert.Equal(t, "secret-value", env.MustSecret[string]("TEST_ENV").Reveal())
+	t.Setenv("TEST_ENV", "1m")
+	assert.Equal(t, time.Minute, env.MustSecret[time.Duration]("TEST_ENV").Reveal())
+}
+
+func TestLookupSecret(t *testing.T) {
+	_, err := env.LookupSecret[string]("TEST_ENV_2")
+	assert.True(t, errors.Is(err, env.ErrNotSet))
+	t.Setenv("TEST_ENV", "99999999999")
+	_, err = env.LookupSecret[int32]("TEST_ENV")
+	assert.EqualError(t, err, `ENV "TEST_ENV" must be a valid int32: value out of range`)
+
+	t.Setenv("TEST_ENV", "true")
+	s, err := env.LookupSecret[bool]("TEST_ENV")
+	assert.NoError(t, err)
+	assert.True(t, s.Reveal())
+}
+
+func TestShortSecretsNotRedacted(t *testing.T) {
+	t.Setenv("TEST_SECRET_SHORT_PIN", "1")
+	t.Setenv("TEST_SECRET_SHORT_FLAG", "true")
+	t.Setenv("TEST_SECRET_SHORT_CODE", "8181")
+
+	assert.Equal(t, 1, env.MustSecret[int]("TEST_SECRET_SHORT_PIN").Reveal())
+	assert.True(t, env.MustSecret[bool]("TEST_SECRET_SHORT_FLAG").Reveal())
+	assert.Equal(t, "8181", env.MustSecret[string]("TEST_SECRET_SHORT_CODE").Reveal())
+
+	var s env.Secret[int]
+	assert.NoError(t, s.UnmarshalText([]byte("10")))
+
+	out := `{"ok":true,"retries":10,"port":8181}`
+	assert.Equal(t, out, env.NewRedactor().Redact(out))
+}
@@ -0,0 +1,151 @@
+package env
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SignatureComment is the prefix of the trailing signature comment in signed dotenv files.
+const SignatureComment = "# env-signature: "
+
+// SignatureExt is the extension of detached dotenv signature files, e.g. ".env.sig".
+const SignatureExt = ".sig"
+
+// ErrInvalidSignature is returned when a dotenv file doesn't match its signature.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// CanonicalDotenv returns the canonical form of dotenv file contents, which is signed.
+// Comments, blank lines, whitespace and quoting are dropped, keys are sorted,
+// the last definition of a duplicated key wins, and every value is quoted
+// the same way, so reformatting a file doesn't invalidate its signature
+// while changing any variable does.
+func CanonicalDotenv(data []byte) ([]byte, error) {
+	entries, err := ParseDotenv(data)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(entries))
+	for _, e := range entries {
+		values[e.Key] = e.Value
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b bytes.Buffer
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(values[k]))
+		b.WriteByte('\n')
+	}
+
+	return b.Bytes(), nil
+}
+
+// SignDotenv signs the canonical form of dotenv file contents
+// and returns the base64 encoded signature.
+func SignDotenv(data []byte, key ed25519.PrivateKey) (string, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return "", fmt.Errorf("private key must be %d bytes long, got %d", ed25519.PrivateKeySize, len(key))
+	}
+
+	canonical, err := CanonicalDotenv(data)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(key, canonical)), nil
+}
+
+// VerifyDotenv verifies the base64 encoded signature of dotenv file contents.
+func VerifyDotenv(data []byte, signature string, key ed25519.PublicKey) error {
+	if len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("public key must be %d bytes long, got %d", ed25519.PublicKeySize, len(key))
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return fmt.Errorf("%w: signature must be base64 encoded", ErrInvalidSignature)
+	}
+
+	canonical, err := CanonicalDotenv(data)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(key, canonical, sig) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// SignDotenvFile signs the dotenv file. If detached is true, the signature is written
+// to the "<path>.sig" file, otherwise it's stored in the trailing signature comment
+// replacing the previous one.
+func SignDotenvFile(path string, key ed25519.PrivateKey, detached bool) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read dotenv file: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read dotenv file: %w", err)
+	}
+
+	sig, err := SignDotenv(data, key)
+	if err != nil {
+		return fmt.Errorf("dotenv file %q: %w", path, err)
+	}
+
+	if detached {
+		return os.WriteFile(path+SignatureExt, []byte(sig+"\n"), 0o644)
+	}
+
+	body, _ := splitSignatureComment(data)
+	body = append(bytes.TrimRight(body, "\n"), '\n')
+	body = append(body, SignatureComment+sig+"\n"...)
+
+	return os.WriteFile(path, body, info.Mode().Perm())
+}
+
+// splitSignatureComment separates the trailing signature comment from the file contents.
+func splitSignatureComment(data []byte) ([]byte, string) {
+	trimmed := bytes.TrimRight(data, " \t\r\n")
+	i := bytes.LastIndexByte(trimmed, '\n')
+	last := string(bytes.TrimSpace(trimmed[i+1:]))
+	if !strings.HasPrefix(last, SignatureComment) {
+		return data, ""
+	}
+	return trimmed[:i+1], strings.TrimPrefix(last, SignatureComment)
+}
+
+// verifyDotenvFile verifies the file contents against the trailing signature comment
+// or the detached signature file.
+func verifyDotenvFile(path string, data []byte, key ed25519.PublicKey) error {
+	if _, sig := splitSignatureComment(data); sig != "" {
+		return VerifyDotenv(data, sig, key)
+	}
+
+	sig, err := os.ReadFile(path + SignatureExt)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: signature not found", ErrInvalidSignature)
+		}
+		return fmt.Errorf("failed to read signature file: %w", err)
+	}
+
+	return VerifyDotenv(data, string(sig), key)
+}
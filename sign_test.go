@@ -0,0 +1,102 @@
+package env_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalDotenv(t *testing.T) {
+	a, err := env.CanonicalDotenv([]byte("B=2\n# comment\nA=1\n"))
+	require.NoError(t, err)
+	b, err := env.CanonicalDotenv([]byte("export A = \"1\"\n\n  B='2'   # two\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "A=\"1\"\nB=\"2\"\n", string(a))
+	assert.Equal(t, a, b)
+}
+
+func TestSignDotenv(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	data := []byte("DB_HOST=db\nDB_PORT=5432\n")
+	sig, err := env.SignDotenv(data, priv)
+	require.NoError(t, err)
+
+	assert.NoError(t, env.VerifyDotenv(data, sig, pub))
+	assert.NoError(t, env.VerifyDotenv([]byte("DB_PORT=5432 # reformatted\nDB_HOST=\"db\"\n"), sig, pub))
+	assert.True(t, errors.Is(env.VerifyDotenv([]byte("DB_HOST=evil\nDB_PORT=5432\n"), sig, pub), env.ErrInvalidSignature))
+	assert.True(t, errors.Is(env.VerifyDotenv(data, "not base64!", pub), env.ErrInvalidSignature))
+
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	assert.True(t, errors.Is(env.VerifyDotenv(data, sig, otherPub), env.ErrInvalidSignature))
+
+	// wrong-length keys are refused instead of panicking
+	assert.EqualError(t, env.VerifyDotenv(data, sig, ed25519.PublicKey("short")), "public key must be 32 bytes long, got 5")
+	_, err = env.SignDotenv(data, ed25519.PrivateKey("short"))
+	assert.Error(t, err)
+}
+
+func TestLoadSignedDotenv(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(path, []byte("TEST_SIGNED_A=approved\n"), 0o600))
+
+	// unsigned file is refused
+	err = env.LoadDotenv(path, env.WithSignature(pub))
+	assert.True(t, errors.Is(err, env.ErrInvalidSignature))
+
+	// trailing signature comment
+	require.NoError(t, env.SignDotenvFile(path, priv, false))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "TEST_SIGNED_A=approved\n"+env.SignatureComment))
+
+	t.Setenv("TEST_SIGNED_A", "")
+	assert.NoError(t, env.LoadDotenv(path, env.WithSignature(pub), env.Override()))
+	assert.Equal(t, "approved", env.MustString("TEST_SIGNED_A"))
+
+	// re-signing replaces the previous signature
+	require.NoError(t, env.SignDotenvFile(path, priv, false))
+	resigned, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(resigned), env.SignatureComment))
+
+	// tampered file is refused
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(data), "approved", "tampered", 1)), 0o600))
+	err = env.LoadDotenv(path, env.WithSignature(pub), env.Override())
+	assert.True(t, errors.Is(err, env.ErrInvalidSignature))
+	assert.Equal(t, "approved", env.MustString("TEST_SIGNED_A"))
+
+	// detached signature
+	detached := filepath.Join(dir, ".env.production")
+	require.NoError(t, os.WriteFile(detached, []byte("TEST_SIGNED_B=approved\n"), 0o600))
+	require.NoError(t, env.SignDotenvFile(detached, priv, true))
+	_, err = os.Stat(detached + env.SignatureExt)
+	assert.NoError(t, err)
+
+	t.Setenv("TEST_SIGNED_B", "")
+	assert.NoError(t, env.LoadDotenv(detached, env.WithSignature(pub), env.Override()))
+	assert.Equal(t, "approved", env.MustString("TEST_SIGNED_B"))
+
+	require.NoError(t, os.WriteFile(detached, []byte("TEST_SIGNED_B=tampered\n"), 0o600))
+	_, err = env.ReadDotenv(detached, env.WithSignature(pub))
+	assert.True(t, errors.Is(err, env.ErrInvalidSignature))
+
+	assert.NotPanics(t, func() {
+		_, err = env.ReadDotenv(detached, env.WithSignature([]byte("short")))
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// EnvSnapshot is an immutable copy of the process environment.
+// It's a read-only Source, so Get* and Must* functions can read a consistent view across many keys:
+//
+//	env.SetSource(env.Snapshot())
+type EnvSnapshot struct {
+	values map[string]string
+}
+
+// Snapshot captures the complete process environment.
+func Snapshot() *EnvSnapshot {
+	environ := os.Environ()
+	s := &EnvSnapshot{values: make(map[string]string, len(environ))}
+	for _, kv := range environ {
+		key, value, _ := strings.Cut(kv, "=")
+		s.values[key] = value
+	}
+	return s
+}
+
+// Lookup implements Source.
+func (s *EnvSnapshot) Lookup(key string) (string, bool, error) {
+	value, ok := s.values[key]
+	return value, ok, nil
+}
+
+// Keys implements KeyLister, it returns the sorted keys of the snapshot.
+func (s *EnvSnapshot) Keys() ([]string, error) {
+	return s.sortedKeys(), nil
+}
+
+func (s *EnvSnapshot) sortedKeys() []string {
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Restore makes the process environment exactly match the snapshot:
+// variables set after the snapshot was taken are unset, changed and removed ones are set back.
+func (s *EnvSnapshot) Restore() error {
+	current := Snapshot()
+	for key := range current.values {
+		if _, ok := s.values[key]; !ok {
+			if err := os.Unsetenv(key); err != nil {
+				return fmt.Errorf("failed to unset ENV %q: %w", key, err)
+			}
+		}
+	}
+	for key, value := range s.values {
+		if v, ok := current.values[key]; ok && v == value {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("failed to set ENV %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// SnapshotChange is a variable which differs between two snapshots.
+type SnapshotChange struct {
+	Key string
+	Old string
+	New string
+}
+
+// SnapshotDiff lists the differences between two snapshots, sorted by key.
+type SnapshotDiff struct {
+	Added   []SnapshotChange
+	Removed []SnapshotChange
+	Changed []SnapshotChange
+}
+
+// Empty reports whether the snapshots are equal.
+func (d SnapshotDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String formats the diff, one variable per line: "+KEY=new", "-KEY=old" or "~KEY=old -> new".
+func (d SnapshotDiff) String() string {
+	var lines []string
+	for _, c := range d.Added {
+		lines = append(lines, fmt.Sprintf("+%s=%s", c.Key, c.New))
+	}
+	for _, c := range d.Removed {
+		lines = append(lines, fmt.Sprintf("-%s=%s", c.Key, c.Old))
+	}
+	for _, c := range d.Changed {
+		lines = append(lines, fmt.Sprintf("~%s=%s -> %s", c.Key, c.Old, c.New))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DiffOption configures Diff.
+type DiffOption func(*diffOptions)
+
+type diffOptions struct {
+	mask func(key, value string) string
+}
+
+// MaskSecrets masks the values of keys matching DefaultSecretKeyPatterns
+// and the values containing registered secrets.
+func MaskSecrets() DiffOption {
+	return func(o *diffOptions) { o.mask = maskValue }
+}
+
+// MaskWith masks the values with the redactor, see Redactor.MaskValue,
+// e.g. to mask keys matching custom patterns.
+func MaskWith(r *Redactor) DiffOption {
+	return func(o *diffOptions) { o.mask = r.MaskValue }
+}
+
+// Diff returns the changes from s to other: added keys exist in other only,
+// removed keys exist in s only.
+func (s *EnvSnapshot) Diff(other *EnvSnapshot, opts ...DiffOption) SnapshotDiff {
+	var o diffOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	mask := func(key, value string) string {
+		if o.mask != nil {
+			return o.mask(key, value)
+		}
+		return value
+	}
+
+	var d SnapshotDiff
+	for _, key := range s.sortedKeys() {
+		old := s.values[key]
+		value, ok := other.values[key]
+		switch {
+		case !ok:
+			d.Removed = append(d.Removed, SnapshotChange{Key: key, Old: mask(key, old)})
+		case value != old:
+			d.Changed = append(d.Changed, SnapshotChange{Key: key, Old: mask(key, old), New: mask(key, value)})
+		}
+	}
+	for _, key := range other.sortedKeys() {
+		if _, ok := s.values[key]; !ok {
+			d.Added = append(d.Added, SnapshotChange{Key: key, New: mask(key, other.values[key])})
+		}
+	}
+	return d
+}
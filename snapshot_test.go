@@ -0,0 +1,67 @@
+package env_test
+
+import (
+	"os"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Setenv("TEST_SNAPSHOT_KEPT", "kept")
+	t.Setenv("TEST_SNAPSHOT_CHANGED", "before")
+	t.Setenv("TEST_SNAPSHOT_REMOVED", "removed")
+	t.Setenv("TEST_SNAPSHOT_API_TOKEN", "snapshot-token-v1")
+
+	before := env.Snapshot()
+	keys, err := before.Keys()
+	require.NoError(t, err)
+	assert.Contains(t, keys, "TEST_SNAPSHOT_KEPT")
+	keys, err = env.NewEnv(env.NewLayer("snapshot", before)).Keys()
+	require.NoError(t, err)
+	assert.Contains(t, keys, "TEST_SNAPSHOT_KEPT")
+
+	// snapshot is immutable
+	os.Setenv("TEST_SNAPSHOT_CHANGED", "after")
+	os.Unsetenv("TEST_SNAPSHOT_REMOVED")
+	os.Setenv("TEST_SNAPSHOT_ADDED", "added")
+	os.Setenv("TEST_SNAPSHOT_API_TOKEN", "snapshot-token-v2")
+	defer os.Unsetenv("TEST_SNAPSHOT_ADDED")
+
+	value, ok, err := before.Lookup("TEST_SNAPSHOT_CHANGED")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "before", value)
+
+	env.SetSource(before)
+	assert.Equal(t, "before", env.MustString("TEST_SNAPSHOT_CHANGED"))
+	assert.Equal(t, "removed", env.MustString("TEST_SNAPSHOT_REMOVED"))
+	env.SetSource(nil)
+
+	after := env.Snapshot()
+	diff := before.Diff(after)
+	assert.Equal(t, env.SnapshotDiff{
+		Added:   []env.SnapshotChange{{Key: "TEST_SNAPSHOT_ADDED", New: "added"}},
+		Removed: []env.SnapshotChange{{Key: "TEST_SNAPSHOT_REMOVED", Old: "removed"}},
+		Changed: []env.SnapshotChange{
+			{Key: "TEST_SNAPSHOT_API_TOKEN", Old: "snapshot-token-v1", New: "snapshot-token-v2"},
+			{Key: "TEST_SNAPSHOT_CHANGED", Old: "before", New: "after"},
+		},
+	}, diff)
+
+	masked := before.Diff(after, env.MaskSecrets())
+	assert.Equal(t, env.SnapshotChange{Key: "TEST_SNAPSHOT_API_TOKEN", Old: env.Redacted, New: env.Redacted}, masked.Changed[0])
+	assert.NotContains(t, masked.String(), "snapshot-token")
+
+	masked = before.Diff(after, env.MaskWith(env.NewRedactor(env.WithKeyPatterns("*_CHANGED"), env.WithMask("***"))))
+	assert.Equal(t, env.SnapshotChange{Key: "TEST_SNAPSHOT_CHANGED", Old: "***", New: "***"}, masked.Changed[1])
+	assert.Equal(t, "added", masked.Added[0].New)
+
+	require.NoError(t, before.Restore())
+	assert.True(t, before.Diff(env.Snapshot()).Empty())
+	_, exists := os.LookupEnv("TEST_SNAPSHOT_ADDED")
+	assert.False(t, exists)
+	assert.Equal(t, "removed", os.Getenv("TEST_SNAPSHOT_REMOVED"))
+}
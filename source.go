@@ -0,0 +1,222 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Source provides values of environment variables.
+// Lookup returns the value and true if the variable is present,
+// or an error if the value can't be read (e.g. it can't be decrypted or fetched).
+type Source interface {
+	Lookup(key string) (value string, ok bool, err error)
+}
+
+// SourceFunc is an adapter to allow the use of ordinary functions as a Source.
+type SourceFunc func(key string) (string, bool, error)
+
+// Lookup implements Source.
+func (f SourceFunc) Lookup(key string) (string, bool, error) {
+	return f(key)
+}
+
+// OS is the Source reading the process environment with os.LookupEnv.
+var OS Source = osSource{}
+
+type osSource struct{}
+
+// Lookup implements Source.
+func (osSource) Lookup(key string) (string, bool, error) {
+	value, ok := os.LookupEnv(key)
+	return value, ok, nil
+}
+
+// Keys implements KeyLister, it returns the keys of the process environment.
+func (osSource) Keys() ([]string, error) {
+	return environKeys(nil), nil
+}
+
+// environKeys returns the sorted keys of the process environment starting with any of the prefixes,
+// or all keys if there are no prefixes.
+func environKeys(prefixes []string) []string {
+	var keys []string
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if len(prefixes) == 0 || hasAnyPrefix(key, prefixes) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Reloader is implemented by sources which cache values and can re-read them,
+// e.g. dotenv files or remote sources. See Watcher.
+type Reloader interface {
+	Reload() error
+}
+
+// Chain returns a Source which looks the key up in the sources in order:
+// the first source which has the key wins, so earlier sources take precedence.
+// An error of any source consulted before the key is found is returned as is.
+func Chain(sources ...Source) Source {
+	return chain(sources)
+}
+
+type chain []Source
+
+// Lookup implements Source.
+func (c chain) Lookup(key string) (string, bool, error) {
+	for _, s := range c {
+		value, ok, err := s.Lookup(key)
+		if err != nil {
+			return "", false, err
+		}
+		if ok {
+			return value, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// Reload implements Reloader, every source implementing Reloader is reloaded.
+func (c chain) Reload() error {
+	return reloadAll([]Source(c))
+}
+
+// Keys implements KeyLister, keys of the sources implementing KeyLister are listed.
+func (c chain) Keys() ([]string, error) {
+	return listKeys([]Source(c))
+}
+
+// listKeys returns the sorted unique keys of the sources implementing KeyLister.
+func listKeys(sources []Source) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, s := range sources {
+		lister, ok := s.(KeyLister)
+		if !ok {
+			continue
+		}
+		keys, err := lister.Keys()
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			seen[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// wrapper is a Source looking keys up with lookup, which forwards Reload and Keys to the wrapped source.
+type wrapper struct {
+	src    Source
+	lookup SourceFunc
+}
+
+// wrap returns a Source looking keys up with lookup, reloading src and listing its keys.
+func wrap(src Source, lookup SourceFunc) Source {
+	return wrapper{src: src, lookup: lookup}
+}
+
+// Lookup implements Source.
+func (w wrapper) Lookup(key string) (string, bool, error) {
+	return w.lookup(key)
+}
+
+// Reload implements Reloader.
+func (w wrapper) Reload() error {
+	return reloadAll([]Source{w.src})
+}
+
+// Keys implements KeyLister, keys of the wrapped source are listed if it implements KeyLister.
+func (w wrapper) Keys() ([]string, error) {
+	return listKeys([]Source{w.src})
+}
+
+// reloadAll reloads every source implementing Reloader and joins the errors.
+func reloadAll(sources []Source) error {
+	var errs []error
+	for _, s := range sources {
+		if r, ok := s.(Reloader); ok {
+			if err := r.Reload(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MapSource is a Source backed by a map.
+type MapSource map[string]string
+
+// Lookup implements Source.
+func (m MapSource) Lookup(key string) (string, bool, error) {
+	value, ok := m[key]
+	return value, ok, nil
+}
+
+// Keys implements KeyLister.
+func (m MapSource) Keys() ([]string, error) {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+type sourceHolder struct {
+	source Source
+}
+
+var currentSource atomic.Pointer[sourceHolder]
+
+// SetSource sets the Source used by all Get*, Must* and Lookup* functions.
+// Passing nil restores the default OS source.
+func SetSource(s Source) {
+	if s == nil {
+		currentSource.Store(nil)
+		return
+	}
+	currentSource.Store(&sourceHolder{source: s})
+}
+
+// CurrentSource returns the Source used by all Get*, Must* and Lookup* functions.
+func CurrentSource() Source {
+	if h := currentSource.Load(); h != nil {
+		return h.source
+	}
+	return OS
+}
+
+// lookupEnv reads the variable from the current source and registers the key, see Register.
+// If the variable isn't set, its aliases are tried in order, see Alias.
+// If no source has the variable and it was scrubbed from the process environment,
+// it fails with ErrConsumed, see Scrub.
+func lookupEnv(key string, aliases ...string) (string, bool, error) {
+	aliases = append(aliasesOf(key), aliases...)
+	registry.add(key)
+	registry.add(aliases...)
+
+	value, used, err := lookupAliased(CurrentSource(), key, aliases)
+	if err != nil {
+		return "", false, err
+	}
+	if used == "" {
+		return "", false, consumer.check(append([]string{key}, aliases...)...)
+	}
+
+	if err := consumer.afterRead(used, value); err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
@@ -0,0 +1,34 @@
+package env_test
+
+import (
+	"errors"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetSource(t *testing.T) {
+	assert.Equal(t, env.OS, env.CurrentSource())
+
+	env.SetSource(env.SourceFunc(func(key string) (string, bool, error) {
+		switch key {
+		case "TEST_SOURCE_PORT":
+			return "8080", true, nil
+		case "TEST_SOURCE_BROKEN":
+			return "", false, errors.New("source is unavailable")
+		}
+		return "", false, nil
+	}))
+	defer env.SetSource(nil)
+
+	assert.Equal(t, 8080, env.MustInt[int]("TEST_SOURCE_PORT"))
+	assert.Equal(t, "default", env.GetString("TEST_SOURCE_MISSING", "default"))
+	assert.Equal(t, "default", env.GetString("TEST_SOURCE_BROKEN", "default"))
+	assert.PanicsWithError(t, `failed to read required ENV "TEST_SOURCE_BROKEN": source is unavailable`, func() { env.MustString("TEST_SOURCE_BROKEN") })
+	_, err := env.LookupRate("TEST_SOURCE_BROKEN")
+	assert.EqualError(t, err, `failed to read ENV "TEST_SOURCE_BROKEN": source is unavailable`)
+
+	env.SetSource(nil)
+	assert.Equal(t, env.OS, env.CurrentSource())
+}
@@ -0,0 +1,185 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// StrictMode defines how CheckUnknown reports unknown variables.
+type StrictMode int
+
+const (
+	// StrictWarn logs every unknown variable with the logger, see SetLogger, and returns nil.
+	StrictWarn StrictMode = iota
+	// StrictFail returns an *UnknownVarsError listing every unknown variable.
+	StrictFail
+)
+
+type keyRegistry struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+// registry holds the keys read through getters and struct binding, and registered explicitly.
+var registry = &keyRegistry{keys: make(map[string]bool)}
+
+func (r *keyRegistry) add(keys ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range keys {
+		r.keys[key] = true
+	}
+}
+
+func (r *keyRegistry) has(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.keys[key]
+}
+
+// Register marks the keys as known to the application, e.g. variables read with os.Getenv.
+// Keys read with Get*, Must*, Lookup* functions or Bind are registered automatically.
+func Register(keys ...string) {
+	registry.add(keys...)
+}
+
+// Registered returns the sorted registered keys.
+func Registered() []string {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	keys := make([]string, 0, len(registry.keys))
+	for key := range registry.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// UnknownVar is a variable under an owned prefix which the application never registered.
+type UnknownVar struct {
+	Key string
+	// Suggestion is the closest registered key, if any is close enough.
+	Suggestion string
+}
+
+// String formats the variable with the suggestion.
+func (u UnknownVar) String() string {
+	if u.Suggestion != "" {
+		return fmt.Sprintf("%s (did you mean %s?)", u.Key, u.Suggestion)
+	}
+	return u.Key
+}
+
+// UnknownVarsError is returned by CheckUnknown in StrictFail mode.
+type UnknownVarsError struct {
+	Vars []UnknownVar
+}
+
+// Error implements error.
+func (e *UnknownVarsError) Error() string {
+	vars := make([]string, 0, len(e.Vars))
+	for _, u := range e.Vars {
+		vars = append(vars, u.String())
+	}
+	return "unknown ENV: " + strings.Join(vars, ", ")
+}
+
+// FindUnknown returns the variables whose keys start with any of the prefixes and which
+// were never registered, sorted by key. The process environment and the keys of the current
+// source, if it implements KeyLister, are checked. It should be called after the configuration is read.
+func FindUnknown(prefixes ...string) []UnknownVar {
+	candidates := make(map[string]bool)
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		candidates[key] = true
+	}
+	if lister, ok := CurrentSource().(KeyLister); ok {
+		if keys, err := lister.Keys(); err == nil {
+			for _, key := range keys {
+				candidates[key] = true
+			}
+		}
+	}
+
+	known := Registered()
+	var unknown []UnknownVar
+	for key := range candidates {
+		if !hasAnyPrefix(key, prefixes) || registry.has(key) {
+			continue
+		}
+		unknown = append(unknown, UnknownVar{Key: key, Suggestion: suggestKey(key, known)})
+	}
+
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i].Key < unknown[j].Key })
+	return unknown
+}
+
+// CheckUnknown reports the variables found by FindUnknown according to the mode.
+//
+//	cfg := loadConfig() // reads PAYMENTS_TIMEOUT, PAYMENTS_RETRIES, ...
+//	if err := env.CheckUnknown(env.StrictFail, "PAYMENTS_"); err != nil {
+//		log.Fatal(err) // unknown ENV: PAYMENTS_TIMOUT (did you mean PAYMENTS_TIMEOUT?)
+//	}
+func CheckUnknown(mode StrictMode, prefixes ...string) error {
+	unknown := FindUnknown(prefixes...)
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	if mode == StrictFail {
+		return &UnknownVarsError{Vars: unknown}
+	}
+	for _, u := range unknown {
+		logger().Warn("env: unknown variable", "key", u.Key, "suggestion", u.Suggestion)
+	}
+	return nil
+}
+
+func hasAnyPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestKey returns the known key closest to the key by edit distance,
+// or an empty string if none is close enough.
+func suggestKey(key string, known []string) string {
+	best, bestDist := "", len(key)/3+1
+	if bestDist < 3 {
+		bestDist = 3
+	}
+	for _, k := range known {
+		if d := editDistance(key, k); d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
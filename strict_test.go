@@ -0,0 +1,51 @@
+package env_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckUnknown(t *testing.T) {
+	t.Setenv("TEST_STRICT_TIMEOUT", "10s")
+	t.Setenv("TEST_STRICT_TIMOUT", "30s")
+	t.Setenv("TEST_STRICT_RETRIES", "3")
+	t.Setenv("TEST_STRICT_LEGACY", "1")
+	t.Setenv("TEST_STRICT_COMPLETELY_DIFFERENT", "1")
+
+	type config struct {
+		Retries int `env:"TEST_STRICT_RETRIES"`
+	}
+	require.NoError(t, env.Bind(&config{}))
+	assert.Equal(t, 10*time.Second, env.GetDuration("TEST_STRICT_TIMEOUT", time.Second))
+	env.Register("TEST_STRICT_LEGACY")
+	assert.Contains(t, env.Registered(), "TEST_STRICT_RETRIES")
+
+	assert.Equal(t, []env.UnknownVar{
+		{Key: "TEST_STRICT_COMPLETELY_DIFFERENT"},
+		{Key: "TEST_STRICT_TIMOUT", Suggestion: "TEST_STRICT_TIMEOUT"},
+	}, env.FindUnknown("TEST_STRICT_"))
+
+	assert.NoError(t, env.CheckUnknown(env.StrictWarn, "TEST_STRICT_"))
+	assert.NoError(t, env.CheckUnknown(env.StrictFail, "TEST_STRICT_RETRIES"))
+
+	err := env.CheckUnknown(env.StrictFail, "TEST_STRICT_")
+	var unknownErr *env.UnknownVarsError
+	require.True(t, errors.As(err, &unknownErr))
+	assert.Len(t, unknownErr.Vars, 2)
+	assert.EqualError(t, err, "unknown ENV: TEST_STRICT_COMPLETELY_DIFFERENT, TEST_STRICT_TIMOUT (did you mean TEST_STRICT_TIMEOUT?)")
+}
+
+func TestCheckUnknownSource(t *testing.T) {
+	env.SetSource(env.MapSource{"TEST_STRICT_SOURCE_PORT": "80", "TEST_STRICT_SOURCE_PROT": "81"})
+	defer env.SetSource(nil)
+
+	assert.Equal(t, 80, env.MustInt[int]("TEST_STRICT_SOURCE_PORT"))
+	assert.Equal(t, []env.UnknownVar{
+		{Key: "TEST_STRICT_SOURCE_PROT", Suggestion: "TEST_STRICT_SOURCE_PORT"},
+	}, env.FindUnknown("TEST_STRICT_SOURCE_"))
+}
@@ -0,0 +1,643 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version represents a semantic version (https://semver.org/spec/v2.0.0.html),
+// e.g. "1.14.0", "2.0.0-rc.1+build.5".
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease string
+	Build      string
+}
+
+// ParseVersion parses a semantic version value.
+// A leading "v" is allowed, e.g. "v1.2.3".
+func ParseVersion(value string) (Version, error) {
+	v, parts, err := parseVersion(value)
+	if err != nil {
+		return Version{}, err
+	}
+	if parts != 3 {
+		return Version{}, errors.New("version must be in the MAJOR.MINOR.PATCH format")
+	}
+	return v, nil
+}
+
+// parseVersion parses a possibly partial version, e.g. "2" or "2.3",
+// and returns the number of numeric parts found.
+func parseVersion(value string) (Version, int, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(value), "v")
+	if s == "" {
+		return Version{}, 0, errors.New("version must not be empty")
+	}
+
+	var v Version
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		v.Build = s[i+1:]
+		s = s[:i]
+		if err := validateIdentifiers(v.Build, false); err != nil {
+			return Version{}, 0, fmt.Errorf("invalid build metadata: %w", err)
+		}
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.Prerelease = s[i+1:]
+		s = s[:i]
+		if err := validateIdentifiers(v.Prerelease, true); err != nil {
+			return Version{}, 0, fmt.Errorf("invalid prerelease: %w", err)
+		}
+	}
+
+	nums := strings.Split(s, ".")
+	if len(nums) > 3 {
+		return Version{}, 0, errors.New("version must be in the MAJOR.MINOR.PATCH format")
+	}
+	if len(nums) < 3 && (v.Prerelease != "" || v.Build != "") {
+		return Version{}, 0, errors.New("version with prerelease or build metadata must be in the MAJOR.MINOR.PATCH format")
+	}
+
+	dst := []*uint64{&v.Major, &v.Minor, &v.Patch}
+	for i, n := range nums {
+		if !isNumeric(n) || len(n) > 1 && n[0] == '0' {
+			return Version{}, 0, fmt.Errorf("invalid version number %q", n)
+		}
+		res, err := strconv.ParseUint(n, 10, 64)
+		if err != nil {
+			return Version{}, 0, fmt.Errorf("invalid version number %q", n)
+		}
+		*dst[i] = res
+	}
+
+	return v, len(nums), nil
+}
+
+// validateIdentifiers validates dot separated prerelease or build identifiers.
+func validateIdentifiers(s string, prerelease bool) error {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return errors.New("identifier must not be empty")
+		}
+		for _, c := range id {
+			if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '-') {
+				return fmt.Errorf("identifier %q contains invalid characters", id)
+			}
+		}
+		if prerelease && len(id) > 1 && id[0] == '0' && isNumeric(id) {
+			return fmt.Errorf("numeric identifier %q must not have leading zeros", id)
+		}
+	}
+	return nil
+}
+
+// isNumeric reports whether s consists of digits only.
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is less than,
+// equal to or greater than other. Build metadata is ignored.
+func (v Version) Compare(other Version) int {
+	if c := compareUint(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+// LessThan reports whether v is less than other.
+func (v Version) LessThan(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+// GreaterThan reports whether v is greater than other.
+func (v Version) GreaterThan(other Version) bool {
+	return v.Compare(other) > 0
+}
+
+// Equal reports whether v is equal to other. Build metadata is ignored.
+func (v Version) Equal(other Version) bool {
+	return v.Compare(other) == 0
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// comparePrerelease compares prerelease strings according to the semver precedence rules.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, bn := isNumeric(as[i]), isNumeric(bs[i])
+		switch {
+		case an && bn:
+			x, _ := strconv.ParseUint(as[i], 10, 64)
+			y, _ := strconv.ParseUint(bs[i], 10, 64)
+			if c := compareUint(x, y); c != 0 {
+				return c
+			}
+		case an:
+			return -1
+		case bn:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	return compareUint(uint64(len(as)), uint64(len(bs)))
+}
+
+// String returns the version in the MAJOR.MINOR.PATCH[-PRERELEASE][+BUILD] format.
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (v *Version) UnmarshalText(text []byte) error {
+	res, err := ParseVersion(string(text))
+	if err != nil {
+		return err
+	}
+	*v = res
+	return nil
+}
+
+// Constraint represents a set of version constraints, e.g. ">=2.3 <3" or "^1.2 || ~2.0".
+// Space or comma separated conditions are combined with AND, "||" separated groups with OR.
+// Supported operators: =, !=, >, >=, <, <=, ~ and ^.
+// Prerelease versions match only constraints that mention a prerelease
+// of the same MAJOR.MINOR.PATCH, e.g. ">=3.0.0-rc.1".
+type Constraint struct {
+	raw    string
+	groups [][]condition
+}
+
+type condition struct {
+	op      string
+	version Version
+}
+
+// constraintOps is the list of supported operators, longest first.
+var constraintOps = []string{">=", "<=", "!=", "==", ">", "<", "=", "~", "^"}
+
+// ParseConstraint parses a version constraint value.
+func ParseConstraint(value string) (Constraint, error) {
+	c := Constraint{raw: strings.TrimSpace(value)}
+	if c.raw == "" {
+		return Constraint{}, errors.New("constraint must not be empty")
+	}
+
+	for _, group := range strings.Split(c.raw, "||") {
+		tokens := strings.Fields(strings.ReplaceAll(group, ",", " "))
+		if len(tokens) == 0 {
+			return Constraint{}, errors.New("constraint group must not be empty")
+		}
+
+		var conds []condition
+		for i := 0; i < len(tokens); i++ {
+			tok := tokens[i]
+
+			op := ""
+			for _, o := range constraintOps {
+				if strings.HasPrefix(tok, o) {
+					op = o
+					break
+				}
+			}
+			ver := strings.TrimPrefix(tok, op)
+			if ver == "" && i+1 < len(tokens) {
+				// operator separated from version by space, e.g. ">= 2.3"
+				i++
+				ver = tokens[i]
+			}
+
+			parsed, err := parseConditions(op, ver)
+			if err != nil {
+				return Constraint{}, err
+			}
+			conds = append(conds, parsed...)
+		}
+
+		c.groups = append(c.groups, conds)
+	}
+
+	return c, nil
+}
+
+// parseConditions converts an operator and a possibly partial version into
+// a list of primitive conditions.
+func parseConditions(op, ver string) ([]condition, error) {
+	v, parts, err := parseVersion(ver)
+	if err != nil {
+		return nil, fmt.Errorf("invalid constraint version %q: %w", ver, err)
+	}
+
+	switch op {
+	case "", "=", "==":
+		if parts == 3 {
+			return []condition{{"=", v}}, nil
+		}
+		// partial version matches the whole release line, e.g. "=2.3" is ">=2.3.0 <2.4.0"
+		return []condition{{">=", v}, {"<", bump(v, parts)}}, nil
+	case "!=":
+		return []condition{{"!=", v}}, nil
+	case ">", "<=":
+		if parts < 3 {
+			// ">2.3" means greater than any 2.3.x version
+			if op == ">" {
+				return []condition{{">=", bump(v, parts)}}, nil
+			}
+			return []condition{{"<", bump(v, parts)}}, nil
+		}
+		return []condition{{op, v}}, nil
+	case ">=", "<":
+		return []condition{{op, v}}, nil
+	case "~":
+		if parts == 1 {
+			return []condition{{">=", v}, {"<", bump(v, 1)}}, nil
+		}
+		return []condition{{">=", v}, {"<", bump(v, 2)}}, nil
+	case "^":
+		switch {
+		case v.Major > 0 || parts == 1:
+			return []condition{{">=", v}, {"<", bump(v, 1)}}, nil
+		case v.Minor > 0 || parts == 2:
+			return []condition{{">=", v}, {"<", bump(v, 2)}}, nil
+		default:
+			return []condition{{">=", v}, {"<", bump(v, 3)}}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported constraint operator %q", op)
+}
+
+// bump returns the next version after incrementing the given part (1 - major, 2 - minor, 3 - patch).
+func bump(v Version, part int) Version {
+	switch part {
+	case 1:
+		return Version{Major: v.Major + 1}
+	case 2:
+		return Version{Major: v.Major, Minor: v.Minor + 1}
+	default:
+		return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+	}
+}
+
+// Check reports whether the version satisfies the constraint.
+func (c Constraint) Check(v Version) bool {
+	for _, group := range c.groups {
+		if checkConditions(group, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConditions reports whether v satisfies all conditions.
+// Like in npm and Cargo, a prerelease version only satisfies conditions
+// if one of them refers to a prerelease of the same MAJOR.MINOR.PATCH,
+// so ">=2.3 <3" doesn't match "3.0.0-rc.1".
+func checkConditions(conds []condition, v Version) bool {
+	if v.Prerelease != "" {
+		allowed := false
+		for _, cond := range conds {
+			cv := cond.version
+			if cv.Prerelease != "" && cv.Major == v.Major && cv.Minor == v.Minor && cv.Patch == v.Patch {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+
+	for _, cond := range conds {
+		cmp := v.Compare(cond.version)
+		var ok bool
+		switch cond.op {
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the constraint as it was parsed.
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c Constraint) MarshalText() ([]byte, error) {
+	return []byte(c.raw), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Constraint) UnmarshalText(text []byte) error {
+	res, err := ParseConstraint(string(text))
+	if err != nil {
+		return err
+	}
+	*c = res
+	return nil
+}
+
+// GetVersion func returns environment variable value as a parsed semantic version,
+// If variable doesn't exist, is not set or unparsable, returns fallback value
+func GetVersion(key string, fallback Version) Version {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
+		return fallback
+	}
+
+	res, err := ParseVersion(value)
+	if err != nil {
+		return fallback
+	}
+
+	return res
+}
+
+// MustVersion func returns environment variable value as a parsed semantic version,
+// If variable doesn't exist, is not set or unparsable, then panics
+func MustVersion(key string) Version {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
+	if !exists || value == "" {
+		panic(fmt.Errorf("required ENV %q is not set", key))
+	}
+
+	res, err := ParseVersion(value)
+	if err != nil {
+		panic(fmt.Errorf("required ENV %q must be a semantic version but it's %q: %v", key, value, err))
+	}
+
+	return res
+}
+
+// LookupVersion func returns environment variable value as a parsed semantic version.
+// If variable doesn't exist or is not set, returns an error wrapping ErrNotSet.
+// If variable is unparsable, returns a parsing error.
+func LookupVersion(key string) (Version, error) {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		return Version{}, fmt.Errorf("failed to read ENV %q: %w", key, err)
+	}
+	if !exists || value == "" {
+		return Version{}, fmt.Errorf("ENV %q is %w", key, ErrNotSet)
+	}
+
+	res, err := ParseVersion(value)
+	if err != nil {
+		return Version{}, fmt.Errorf("ENV %q must be a semantic version but it's %q: %w", key, value, err)
+	}
+
+	return res, nil
+}
+
+// GetVersions func returns environment variable value as a slice of semantic versions
+// If variable doesn't exist, is not set or unparsable, returns fallback value
+func GetVersions(key string, sep string, fallback []Version) []Version {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
+		return fallback
+	}
+
+	var versions []Version
+	for _, s := range strings.Split(value, sep) {
+		// filter empty strings
+		if s == "" {
+			continue
+		}
+
+		res, err := ParseVersion(s)
+		if err != nil {
+			return fallback
+		}
+
+		versions = append(versions, res)
+	}
+
+	if len(versions) == 0 {
+		return fallback
+	}
+
+	return versions
+}
+
+// MustVersions func returns environment variable value as a slice of semantic versions.
+// If variable doesn't exist, is not set or unparsable, then panics.
+func MustVersions(key string, sep string) []Version {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
+	if !exists || value == "" {
+		panic(fmt.Errorf("required ENV %q is not set", key))
+	}
+
+	var versions []Version
+	for _, s := range strings.Split(value, sep) {
+		// filter empty strings
+		if s == "" {
+			continue
+		}
+
+		res, err := ParseVersion(s)
+		if err != nil {
+			panic(fmt.Errorf("required ENV %q must be a semantic version slice but it's %q: %v", key, value, err))
+		}
+
+		versions = append(versions, res)
+	}
+
+	if len(versions) == 0 {
+		panic(fmt.Errorf("required ENV %q must be a semantic version slice but it's %q", key, value))
+	}
+
+	return versions
+}
+
+// GetConstraint func returns environment variable value as a parsed version constraint,
+// If variable doesn't exist, is not set or unparsable, returns fallback value
+func GetConstraint(key string, fallback Constraint) Constraint {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
+		return fallback
+	}
+
+	res, err := ParseConstraint(value)
+	if err != nil {
+		return fallback
+	}
+
+	return res
+}
+
+// MustConstraint func returns environment variable value as a parsed version constraint,
+// If variable doesn't exist, is not set or unparsable, then panics
+func MustConstraint(key string) Constraint {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
+	if !exists || value == "" {
+		panic(fmt.Errorf("required ENV %q is not set", key))
+	}
+
+	res, err := ParseConstraint(value)
+	if err != nil {
+		panic(fmt.Errorf("required ENV %q must be a version constraint but it's %q: %v", key, value, err))
+	}
+
+	return res
+}
+
+// LookupConstraint func returns environment variable value as a parsed version constraint.
+// If variable doesn't exist or is not set, returns an error wrapping ErrNotSet.
+// If variable is unparsable, returns a parsing error.
+func LookupConstraint(key string) (Constraint, error) {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		return Constraint{}, fmt.Errorf("failed to read ENV %q: %w", key, err)
+	}
+	if !exists || value == "" {
+		return Constraint{}, fmt.Errorf("ENV %q is %w", key, ErrNotSet)
+	}
+
+	res, err := ParseConstraint(value)
+	if err != nil {
+		return Constraint{}, fmt.Errorf("ENV %q must be a version constraint but it's %q: %w", key, value, err)
+	}
+
+	return res, nil
+}
+
+// GetConstraints func returns environment variable value as a slice of version constraints.
+// Since comma combines conditions within a constraint, use another separator, e.g. ";".
+// If variable doesn't exist, is not set or unparsable, returns fallback value
+func GetConstraints(key string, sep string, fallback []Constraint) []Constraint {
+	value, exists, err := lookupEnv(key)
+	if err != nil || !exists || value == "" {
+		return fallback
+	}
+
+	var constraints []Constraint
+	for _, s := range strings.Split(value, sep) {
+		// filter empty strings
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+
+		res, err := ParseConstraint(s)
+		if err != nil {
+			return fallback
+		}
+
+		constraints = append(constraints, res)
+	}
+
+	if len(constraints) == 0 {
+		return fallback
+	}
+
+	return constraints
+}
+
+// MustConstraints func returns environment variable value as a slice of version constraints.
+// Since comma combines conditions within a constraint, use another separator, e.g. ";".
+// If variable doesn't exist, is not set or unparsable, then panics.
+func MustConstraints(key string, sep string) []Constraint {
+	value, exists, err := lookupEnv(key)
+	if err != nil {
+		panic(fmt.Errorf("failed to read required ENV %q: %w", key, err))
+	}
+	if !exists || value == "" {
+		panic(fmt.Errorf("required ENV %q is not set", key))
+	}
+
+	var constraints []Constraint
+	for _, s := range strings.Split(value, sep) {
+		// filter empty strings
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+
+		res, err := ParseConstraint(s)
+		if err != nil {
+			panic(fmt.Errorf("required ENV %q must be a version constraint slice but it's %q: %v", key, value, err))
+		}
+
+		constraints = append(constraints, res)
+	}
+
+	if len(constraints) == 0 {
+		panic(fmt.Errorf("required ENV %q must be a version constraint slice but it's %q", key, value))
+	}
+
+	return constraints
+}
@@ -0,0 +1,213 @@
+package env_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	v, err := env.ParseVersion("1.14.0")
+	assert.NoError(t, err)
+	assert.Equal(t, env.Version{Major: 1, Minor: 14}, v)
+
+	v, err = env.ParseVersion("v2.0.0-rc.1+build.5")
+	assert.NoError(t, err)
+	assert.Equal(t, env.Version{Major: 2, Prerelease: "rc.1", Build: "build.5"}, v)
+	assert.Equal(t, "2.0.0-rc.1+build.5", v.String())
+
+	for _, value := range []string{"", "1", "1.2", "1.2.3.4", "01.2.3", "1.2.x", "1.2.3-", "1.2.3-01", "1.2.3+a..b", "1.2.3-a_b"} {
+		_, err = env.ParseVersion(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	// precedence example from the semver spec
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := env.ParseVersion(ordered[i])
+		b, _ := env.ParseVersion(ordered[i+1])
+		assert.True(t, a.LessThan(b), "%s < %s", a, b)
+		assert.True(t, b.GreaterThan(a), "%s > %s", b, a)
+		assert.Equal(t, 0, a.Compare(a))
+	}
+
+	a, _ := env.ParseVersion("1.0.0+build.1")
+	b, _ := env.ParseVersion("1.0.0+build.2")
+	assert.True(t, a.Equal(b))
+}
+
+func TestParseConstraint(t *testing.T) {
+	cases := []struct {
+		constraint string
+		matches    []string
+		misses     []string
+	}{
+		{">=2.3 <3", []string{"2.3.0", "2.9.9"}, []string{"2.2.9", "3.0.0", "3.0.0-rc.1"}},
+		{">= 2.3, < 3", []string{"2.3.0"}, []string{"3.0.0"}},
+		{"1.2.3", []string{"1.2.3", "1.2.3+build"}, []string{"1.2.4"}},
+		{"=1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"!=1.2.3", []string{"1.2.4"}, []string{"1.2.3"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{"^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"2.0.0", "1.2.2"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{">=3.0.0-rc.1 <3.0.1", []string{"3.0.0-rc.2", "3.0.0"}, []string{"3.0.0-beta"}},
+		{"^1.2 || ~2.0", []string{"1.5.0", "2.0.5"}, []string{"2.1.0", "0.9.0"}},
+	}
+
+	for _, tc := range cases {
+		c, err := env.ParseConstraint(tc.constraint)
+		if !assert.NoError(t, err, tc.constraint) {
+			continue
+		}
+		assert.Equal(t, tc.constraint, c.String())
+		for _, s := range tc.matches {
+			v, _ := env.ParseVersion(s)
+			assert.True(t, c.Check(v), "%s should match %s", s, tc.constraint)
+		}
+		for _, s := range tc.misses {
+			v, _ := env.ParseVersion(s)
+			assert.False(t, c.Check(v), "%s should not match %s", s, tc.constraint)
+		}
+	}
+
+	for _, value := range []string{"", ">=", ">=1.x", "1.2 ||", "=>1.2"} {
+		_, err := env.ParseConstraint(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestVersionText(t *testing.T) {
+	var cfg struct {
+		Min    env.Version    `json:"min"`
+		Compat env.Constraint `json:"compat"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"min":"1.14.0","compat":">=2.3 <3"}`), &cfg))
+	assert.Equal(t, env.Version{Major: 1, Minor: 14}, cfg.Min)
+	assert.True(t, cfg.Compat.Check(env.Version{Major: 2, Minor: 5}))
+
+	data, err := json.Marshal(cfg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"min":"1.14.0","compat":">=2.3 <3"}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"min":"1.14"}`), &cfg))
+}
+
+func TestGetVersion(t *testing.T) {
+	fallback := env.Version{Major: 1}
+	assert.Equal(t, fallback, env.GetVersion("TEST_VERSION", fallback))
+	os.Setenv("TEST_VERSION", "1.14.0")
+	assert.Equal(t, env.Version{Major: 1, Minor: 14}, env.GetVersion("TEST_VERSION", fallback))
+	os.Setenv("TEST_VERSION", "wrong value")
+	assert.Equal(t, fallback, env.GetVersion("TEST_VERSION", fallback))
+}
+
+func TestMustVersion(t *testing.T) {
+	assert.Panics(t, func() { env.MustVersion("TEST_ENV_2") })
+	os.Setenv("TEST_ENV", "wrong value")
+	assert.Panics(t, func() { env.MustVersion("TEST_ENV") })
+
+	os.Setenv("TEST_ENV", "v1.2.3")
+	assert.Equal(t, env.Version{Major: 1, Minor: 2, Patch: 3}, env.MustVersion("TEST_ENV"))
+}
+
+func TestLookupVersion(t *testing.T) {
+	_, err := env.LookupVersion("TEST_ENV_2")
+	assert.True(t, errors.Is(err, env.ErrNotSet))
+	os.Setenv("TEST_ENV", "wrong value")
+	_, err = env.LookupVersion("TEST_ENV")
+	assert.Error(t, err)
+
+	os.Setenv("TEST_ENV", "1.2.3")
+	v, err := env.LookupVersion("TEST_ENV")
+	assert.NoError(t, err)
+	assert.Equal(t, env.Version{Major: 1, Minor: 2, Patch: 3}, v)
+}
+
+func TestGetVersions(t *testing.T) {
+	fallback := []env.Version{{Major: 1}}
+	assert.Equal(t, fallback, env.GetVersions("TEST_VERSIONS", ",", fallback))
+	os.Setenv("TEST_VERSIONS", "1.0.0,2.0.0-rc.1")
+	assert.Equal(t, []env.Version{{Major: 1}, {Major: 2, Prerelease: "rc.1"}}, env.GetVersions("TEST_VERSIONS", ",", fallback))
+	os.Setenv("TEST_VERSIONS", "1.0.0,two")
+	assert.Equal(t, fallback, env.GetVersions("TEST_VERSIONS", ",", fallback))
+}
+
+func TestMustVersions(t *testing.T) {
+	assert.Panics(t, func() { env.MustVersions("TEST_ENV_2", ",") })
+	os.Setenv("TEST_ENV", ",")
+	assert.Panics(t, func() { env.MustVersions("TEST_ENV", ",") })
+	os.Setenv("TEST_ENV", "1.0.0,two")
+	assert.Panics(t, func() { env.MustVersions("TEST_ENV", ",") })
+
+	os.Setenv("TEST_ENV", "1.0.0,2.0.0")
+	assert.Equal(t, []env.Version{{Major: 1}, {Major: 2}}, env.MustVersions("TEST_ENV", ","))
+}
+
+func TestGetConstraint(t *testing.T) {
+	fallback, _ := env.ParseConstraint(">=1")
+	assert.Equal(t, fallback, env.GetConstraint("TEST_CONSTRAINT", fallback))
+	os.Setenv("TEST_CONSTRAINT", ">=2.3 <3")
+	assert.Equal(t, ">=2.3 <3", env.GetConstraint("TEST_CONSTRAINT", fallback).String())
+	os.Setenv("TEST_CONSTRAINT", ">=two")
+	assert.Equal(t, fallback, env.GetConstraint("TEST_CONSTRAINT", fallback))
+}
+
+func TestMustConstraint(t *testing.T) {
+	assert.Panics(t, func() { env.MustConstraint("TEST_ENV_2") })
+	os.Setenv("TEST_ENV", "wrong value")
+	assert.Panics(t, func() { env.MustConstraint("TEST_ENV") })
+
+	os.Setenv("TEST_ENV", "^1.2")
+	c := env.MustConstraint("TEST_ENV")
+	assert.True(t, c.Check(env.Version{Major: 1, Minor: 3}))
+	assert.False(t, c.Check(env.Version{Major: 2}))
+}
+
+func TestLookupConstraint(t *testing.T) {
+	_, err := env.LookupConstraint("TEST_ENV_2")
+	assert.True(t, errors.Is(err, env.ErrNotSet))
+	os.Setenv("TEST_ENV", "wrong value")
+	_, err = env.LookupConstraint("TEST_ENV")
+	assert.Error(t, err)
+
+	os.Setenv("TEST_ENV", "~2.0")
+	c, err := env.LookupConstraint("TEST_ENV")
+	assert.NoError(t, err)
+	assert.True(t, c.Check(env.Version{Major: 2, Patch: 7}))
+}
+
+func TestGetConstraints(t *testing.T) {
+	assert.Nil(t, env.GetConstraints("TEST_CONSTRAINTS", ";", nil))
+	os.Setenv("TEST_CONSTRAINTS", ">=1.0, <2;^3")
+	cs := env.GetConstraints("TEST_CONSTRAINTS", ";", nil)
+	if assert.Len(t, cs, 2) {
+		assert.Equal(t, ">=1.0, <2", cs[0].String())
+		assert.Equal(t, "^3", cs[1].String())
+	}
+	os.Setenv("TEST_CONSTRAINTS", ">=1.0;wrong")
+	assert.Nil(t, env.GetConstraints("TEST_CONSTRAINTS", ";", nil))
+}
+
+func TestMustConstraints(t *testing.T) {
+	assert.Panics(t, func() { env.MustConstraints("TEST_ENV_2", ";") })
+	os.Setenv("TEST_ENV", ";")
+	assert.Panics(t, func() { env.MustConstraints("TEST_ENV", ";") })
+	os.Setenv("TEST_ENV", "^1;wrong")
+	assert.Panics(t, func() { env.MustConstraints("TEST_ENV", ";") })
+
+	os.Setenv("TEST_ENV", "^1;^2")
+	assert.Len(t, env.MustConstraints("TEST_ENV", ";"), 2)
+}
@@ -0,0 +1,311 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Watcher re-reads the current source and updates the watched values on reload.
+// Reloads are triggered by Reload or by the triggers passed to Start.
+type Watcher struct {
+	onError func(error)
+
+	mu     sync.Mutex // serializes reloads
+	subsMu sync.Mutex
+	subs   map[uint64]func() error
+	nextID uint64
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+// WatcherOption configures a Watcher.
+type WatcherOption func(*Watcher)
+
+// WithErrorHandler sets the function called with reload errors, e.g. rejected invalid values.
+// By default errors are logged with the logger, see SetLogger.
+func WithErrorHandler(fn func(error)) WatcherOption {
+	return func(w *Watcher) { w.onError = fn }
+}
+
+// DefaultWatcher is the Watcher used by Watch.
+var DefaultWatcher = NewWatcher()
+
+// NewWatcher creates a new Watcher, it does nothing until Start or Reload is called.
+func NewWatcher(opts ...WatcherOption) *Watcher {
+	w := &Watcher{
+		onError: func(err error) { logger().Warn("env: reload failed", "error", err) },
+		subs:    make(map[uint64]func() error),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Subscribe registers the function called on every reload, after the current source is reloaded.
+// It returns a function which cancels the subscription.
+func (w *Watcher) Subscribe(fn func() error) (cancel func()) {
+	w.subsMu.Lock()
+	defer w.subsMu.Unlock()
+	id := w.nextID
+	w.nextID++
+	w.subs[id] = fn
+	return func() {
+		w.subsMu.Lock()
+		defer w.subsMu.Unlock()
+		delete(w.subs, id)
+	}
+}
+
+// Reload reloads the current source if it implements Reloader, then runs the subscriptions.
+// Errors are passed to the error handler and returned joined.
+func (w *Watcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var errs []error
+	if r, ok := CurrentSource().(Reloader); ok {
+		if err := r.Reload(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to reload source: %w", err))
+		}
+	}
+
+	w.subsMu.Lock()
+	subs := make([]func() error, 0, len(w.subs))
+	for _, fn := range w.subs {
+		subs = append(subs, fn)
+	}
+	w.subsMu.Unlock()
+
+	for _, fn := range subs {
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, err := range errs {
+		w.onError(err)
+	}
+	return errors.Join(errs...)
+}
+
+// Trigger calls reload whenever a reload is due, until ctx is done.
+type Trigger func(ctx context.Context, reload func())
+
+// Start runs the triggers in background goroutines until Stop is called.
+//
+//	env.DefaultWatcher.Start(env.OnSignal(), env.OnFileChange(time.Second, ".env"))
+//	defer env.DefaultWatcher.Stop()
+func (w *Watcher) Start(triggers ...Trigger) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	prev := w.cancel
+	w.cancel = func() {
+		if prev != nil {
+			prev()
+		}
+		cancel()
+	}
+
+	for _, t := range triggers {
+		w.done.Add(1)
+		go func(t Trigger) {
+			defer w.done.Done()
+			t(ctx, func() { _ = w.Reload() })
+		}(t)
+	}
+}
+
+// Stop stops the triggers and waits for them to return.
+func (w *Watcher) Stop() {
+	w.mu.Lock()
+	cancel := w.cancel
+	w.cancel = nil
+	w.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	w.done.Wait()
+}
+
+// OnSignal triggers a reload when the process receives one of the signals, SIGHUP by default.
+func OnSignal(sigs ...os.Signal) Trigger {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+	return func(ctx context.Context, reload func()) {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, sigs...)
+		defer signal.Stop(ch)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ch:
+				reload()
+			}
+		}
+	}
+}
+
+// Every triggers a reload on every interval.
+func Every(interval time.Duration) Trigger {
+	return func(ctx context.Context, reload func()) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				reload()
+			}
+		}
+	}
+}
+
+// OnFileChange triggers a reload when the modification time or the size of any file changes.
+// Files are polled every interval, symlinks are followed, so Kubernetes "..data" swaps are detected.
+// Changes are detected since the trigger is created.
+func OnFileChange(interval time.Duration, paths ...string) Trigger {
+	stamps := fileStamps(paths)
+	return func(ctx context.Context, reload func()) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if current := fileStamps(paths); !reflect.DeepEqual(current, stamps) {
+					stamps = current
+					reload()
+				}
+			}
+		}
+	}
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// fileStamps returns the stamps of the existing files.
+func fileStamps(paths []string) map[string]fileStamp {
+	stamps := make(map[string]fileStamp, len(paths))
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil {
+			stamps[p] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return stamps
+}
+
+// Value is a watched variable, Load returns the latest valid value.
+type Value[T any] struct {
+	key      string
+	parse    func(string) (T, error)
+	onChange func(old, new T)
+	value    atomic.Pointer[T]
+	cancel   func()
+}
+
+// Watch reads the variable and subscribes it to DefaultWatcher, see WatchWith.
+//
+//	timeout, err := env.Watch("HTTP_TIMEOUT", time.ParseDuration, func(old, new time.Duration) {
+//		log.Printf("HTTP_TIMEOUT changed from %s to %s", old, new)
+//	})
+//	...
+//	client.Timeout = timeout.Load()
+func Watch[T any](key string, parse func(string) (T, error), onChange func(old, new T)) (*Value[T], error) {
+	return WatchWith(DefaultWatcher, key, parse, onChange)
+}
+
+// WatchWith reads the variable and subscribes it to the watcher: on every reload the variable
+// is read and parsed again. Invalid or missing values are rejected and the last valid value is kept.
+// onChange, if not nil, is called after a new value is published.
+// If parse is nil, the value is parsed like Get* functions do: strings, booleans,
+// numbers, durations and encoding.TextUnmarshaler implementations are supported.
+func WatchWith[T any](w *Watcher, key string, parse func(string) (T, error), onChange func(old, new T)) (*Value[T], error) {
+	if parse == nil {
+		parse = parseValue[T]
+	}
+
+	v := &Value[T]{key: key, parse: parse, onChange: onChange}
+	value, err := v.read()
+	if err != nil {
+		return nil, err
+	}
+	v.value.Store(&value)
+	v.cancel = w.Subscribe(v.reload)
+
+	return v, nil
+}
+
+// Key returns the watched variable name.
+func (v *Value[T]) Key() string {
+	return v.key
+}
+
+// Load returns the latest valid value.
+func (v *Value[T]) Load() T {
+	return *v.value.Load()
+}
+
+// Unwatch stops updating the value.
+func (v *Value[T]) Unwatch() {
+	v.cancel()
+}
+
+func (v *Value[T]) read() (T, error) {
+	var zero T
+
+	value, exists, err := lookupEnv(v.key)
+	if err != nil {
+		return zero, err
+	}
+	if !exists {
+		return zero, fmt.Errorf("ENV %q: %w", v.key, ErrNotSet)
+	}
+
+	res, err := v.parse(value)
+	if err != nil {
+		return zero, fmt.Errorf("ENV %q: invalid value: %w", v.key, err)
+	}
+
+	return res, nil
+}
+
+func (v *Value[T]) reload() error {
+	value, err := v.read()
+	if err != nil {
+		return fmt.Errorf("rejected new value, keeping the last valid one: %w", err)
+	}
+
+	old := v.Load()
+	if reflect.DeepEqual(old, value) {
+		return nil
+	}
+
+	v.value.Store(&value)
+	if v.onChange != nil {
+		v.onChange(old, value)
+	}
+
+	return nil
+}
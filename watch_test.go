@@ -0,0 +1,99 @@
+package env_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	env "github.com/dmitrymomot/go-env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	t.Setenv("TEST_WATCH_TIMEOUT", "1s")
+
+	var reloadErr error
+	w := env.NewWatcher(env.WithErrorHandler(func(err error) { reloadErr = err }))
+
+	var changes [][2]time.Duration
+	v, err := env.WatchWith(w, "TEST_WATCH_TIMEOUT", time.ParseDuration, func(old, new time.Duration) {
+		changes = append(changes, [2]time.Duration{old, new})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "TEST_WATCH_TIMEOUT", v.Key())
+	assert.Equal(t, time.Second, v.Load())
+
+	// unchanged value
+	assert.NoError(t, w.Reload())
+	assert.Empty(t, changes)
+
+	t.Setenv("TEST_WATCH_TIMEOUT", "2s")
+	assert.NoError(t, w.Reload())
+	assert.Equal(t, 2*time.Second, v.Load())
+	assert.Equal(t, [][2]time.Duration{{time.Second, 2 * time.Second}}, changes)
+
+	// invalid value is rejected
+	t.Setenv("TEST_WATCH_TIMEOUT", "soon")
+	assert.Error(t, w.Reload())
+	assert.Error(t, reloadErr)
+	assert.Equal(t, 2*time.Second, v.Load())
+
+	// missing value is rejected
+	os.Unsetenv("TEST_WATCH_TIMEOUT")
+	assert.Error(t, w.Reload())
+	assert.Equal(t, 2*time.Second, v.Load())
+
+	v.Unwatch()
+	t.Setenv("TEST_WATCH_TIMEOUT", "3s")
+	assert.NoError(t, w.Reload())
+	assert.Equal(t, 2*time.Second, v.Load())
+
+	_, err = env.Watch[int]("TEST_WATCH_MISSING", nil, nil)
+	assert.True(t, errors.Is(err, env.ErrNotSet))
+}
+
+func TestWatcherDotenv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("TEST_WATCH_LIMIT=10\n"), 0o600))
+
+	layer, err := env.DotenvLayer(path)
+	require.NoError(t, err)
+	env.SetSource(env.NewEnv(layer))
+	defer env.SetSource(nil)
+
+	w := env.NewWatcher(env.WithErrorHandler(func(error) {}))
+	var changed atomic.Int32
+	v, err := env.WatchWith[int](w, "TEST_WATCH_LIMIT", nil, func(old, new int) { changed.Add(1) })
+	require.NoError(t, err)
+	assert.Equal(t, 10, v.Load())
+
+	w.Start(env.OnFileChange(5*time.Millisecond, path))
+	defer w.Stop()
+
+	// different size, so the change is detected regardless of the mtime resolution
+	require.NoError(t, os.WriteFile(path, []byte("TEST_WATCH_LIMIT=200\n"), 0o600))
+	assert.Eventually(t, func() bool { return v.Load() == 200 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(1), changed.Load())
+}
+
+func TestWatcherTriggers(t *testing.T) {
+	w := env.NewWatcher()
+	var reloads atomic.Int32
+	cancel := w.Subscribe(func() error {
+		reloads.Add(1)
+		return nil
+	})
+	defer cancel()
+
+	w.Start(env.OnSignal(), env.Every(5*time.Millisecond))
+	assert.Eventually(t, func() bool { return reloads.Load() >= 2 }, time.Second, 5*time.Millisecond)
+	w.Stop()
+
+	n := reloads.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, n, reloads.Load())
+}